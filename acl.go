@@ -0,0 +1,101 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// xsiNamespace qualifies the type attribute of a Grantee.
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// Grantee types.
+const (
+	GranteeCanonicalUser = "CanonicalUser"
+	GranteeGroup         = "Group"
+	GranteeEmail         = "AmazonCustomerByEmail"
+)
+
+// ACLGrant is the access control list of an object:
+// its owner and the permissions granted to others.
+type ACLGrant struct {
+	OwnerID          string
+	OwnerDisplayName string
+	Grants           []Grant
+}
+
+// Grant gives Permission, one of FULL_CONTROL, READ, WRITE,
+// READ_ACP or WRITE_ACP, to a Grantee.
+type Grant struct {
+	Grantee    Grantee `xml:"Grantee"`
+	Permission string  `xml:"Permission"`
+}
+
+// Grantee is identified by ID, URI or EmailAddress
+// depending on its Type.
+type Grantee struct {
+	Type         string `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr"`
+	ID           string `xml:"ID,omitempty"`
+	DisplayName  string `xml:"DisplayName,omitempty"`
+	URI          string `xml:"URI,omitempty"`
+	EmailAddress string `xml:"EmailAddress,omitempty"`
+}
+
+// MarshalXML implements xml.Marshaler. It writes the type with
+// the usual xsi prefix, which encoding/xml cannot produce.
+func (g Grantee) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = []xml.Attr{
+		{Name: xml.Name{Local: "xmlns:xsi"}, Value: xsiNamespace},
+		{Name: xml.Name{Local: "xsi:type"}, Value: g.Type},
+	}
+	return e.EncodeElement(struct {
+		ID           string `xml:"ID,omitempty"`
+		DisplayName  string `xml:"DisplayName,omitempty"`
+		URI          string `xml:"URI,omitempty"`
+		EmailAddress string `xml:"EmailAddress,omitempty"`
+	}{g.ID, g.DisplayName, g.URI, g.EmailAddress}, start)
+}
+
+// accessControlPolicy is the XML representation of an ACLGrant.
+type accessControlPolicy struct {
+	XMLName xml.Name `xml:"AccessControlPolicy"`
+	Owner   struct {
+		ID          string `xml:"ID"`
+		DisplayName string `xml:"DisplayName,omitempty"`
+	} `xml:"Owner"`
+	Grants []Grant `xml:"AccessControlList>Grant"`
+}
+
+// GetObjectACL makes a GET call and returns the access control list of an object.
+func (s3 *S3) GetObjectACL(bucket, key string) (ACLGrant, error) {
+	var policy accessControlPolicy
+	if err := s3.doXML(http.MethodGet, bucket, key, "?acl", nil, nil, &policy); err != nil {
+		return ACLGrant{}, err
+	}
+	return ACLGrant{
+		OwnerID:          policy.Owner.ID,
+		OwnerDisplayName: policy.Owner.DisplayName,
+		Grants:           policy.Grants,
+	}, nil
+}
+
+// PutObjectACL makes a PUT call to replace the access control
+// list of an object with acl. acl.OwnerID must be set to the
+// canonical ID of the owner, as returned by GetObjectACL.
+func (s3 *S3) PutObjectACL(bucket, key string, acl ACLGrant) error {
+	var policy accessControlPolicy
+	policy.Owner.ID = acl.OwnerID
+	policy.Owner.DisplayName = acl.OwnerDisplayName
+	policy.Grants = acl.Grants
+	return s3.doXML(http.MethodPut, bucket, key, "?acl", nil, policy, nil)
+}
+
+// PutCannedObjectACL makes a PUT call to replace the access control
+// list of an object with a canned ACL, such as "private" or "public-read".
+func (s3 *S3) PutCannedObjectACL(bucket, key, cannedACL string) error {
+	header := http.Header{}
+	header.Set("x-amz-acl", cannedACL)
+	return s3.doXML(http.MethodPut, bucket, key, "?acl", header, nil, nil)
+}
@@ -0,0 +1,70 @@
+package gos3
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestS3_ObjectACL(t *testing.T) {
+	stored := []byte(`<AccessControlPolicy xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+<Owner><ID>owner-id</ID><DisplayName>owner</DisplayName></Owner>
+<AccessControlList>
+<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="CanonicalUser"><ID>owner-id</ID><DisplayName>owner</DisplayName></Grantee><Permission>FULL_CONTROL</Permission></Grant>
+</AccessControlList>
+</AccessControlPolicy>`)
+	var cannedACL string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["acl"]; !ok || r.URL.Path != "/bucket/test.txt" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			if cannedACL = r.Header.Get("x-amz-acl"); cannedACL == "" {
+				stored, _ = ioutil.ReadAll(r.Body)
+			}
+		case http.MethodGet:
+			w.Write(stored)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	acl, err := s3.GetObjectACL("bucket", "test.txt")
+	if err != nil {
+		t.Fatalf("S3.GetObjectACL() error = %v", err)
+	}
+	if acl.OwnerID != "owner-id" || len(acl.Grants) != 1 || acl.Grants[0].Grantee.Type != GranteeCanonicalUser {
+		t.Fatalf("S3.GetObjectACL() = %+v", acl)
+	}
+
+	acl.Grants = append(acl.Grants, Grant{
+		Grantee:    Grantee{Type: GranteeGroup, URI: "http://acs.amazonaws.com/groups/global/AllUsers"},
+		Permission: "READ",
+	})
+	if err := s3.PutObjectACL("bucket", "test.txt", acl); err != nil {
+		t.Fatalf("S3.PutObjectACL() error = %v", err)
+	}
+	got, err := s3.GetObjectACL("bucket", "test.txt")
+	if err != nil {
+		t.Fatalf("S3.GetObjectACL() error = %v", err)
+	}
+	if !bytes.Contains(stored, []byte(`<Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group">`)) {
+		t.Errorf("S3.PutObjectACL() sent = %s", stored)
+	}
+	if !reflect.DeepEqual(got, acl) {
+		t.Errorf("S3.GetObjectACL() = %+v, want %+v", got, acl)
+	}
+
+	if err := s3.PutCannedObjectACL("bucket", "test.txt", "public-read"); err != nil {
+		t.Fatalf("S3.PutCannedObjectACL() error = %v", err)
+	}
+	if cannedACL != "public-read" {
+		t.Errorf("x-amz-acl = %q, want public-read", cannedACL)
+	}
+}
@@ -0,0 +1,135 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"fmt"
+	"strings"
+)
+
+// objectLambdaService is the service of Object Lambda access
+// points, used in their ARNs, hosts and signatures.
+const objectLambdaService = "s3-object-lambda"
+
+// globalRegion is the region for which requests to the
+// global endpoint of multi-region access points are signed.
+const globalRegion = "us-east-1"
+
+// accessPointARN is a parsed access point ARN, eg.
+// arn:aws:s3-object-lambda:us-east-1:123456789012:accesspoint/name.
+// Multi-region access points have no region and their
+// name is an alias, eg. arn:aws:s3::123456789012:accesspoint/mfzwi23gnjvgw.mrap.
+type accessPointARN struct {
+	Partition string
+	Service   string
+	Region    string
+	AccountID string
+	Name      string
+}
+
+// isARN reports whether bucket is an ARN
+// rather than the name of a bucket.
+func isARN(bucket string) bool {
+	return strings.HasPrefix(bucket, "arn:")
+}
+
+// parseAccessPointARN parses an access point ARN of the
+// s3 or s3-object-lambda service, or of a multi-region
+// access point.
+func parseAccessPointARN(s string) (accessPointARN, error) {
+	parts := strings.SplitN(s, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[1] == "" {
+		return accessPointARN{}, fmt.Errorf("%w: %s", ErrInvalidARN, s)
+	}
+	a := accessPointARN{
+		Partition: parts[1],
+		Service:   parts[2],
+		Region:    parts[3],
+		AccountID: parts[4],
+	}
+
+	// The resource is accesspoint/<name> or accesspoint:<name>.
+	resource := parts[5]
+	if strings.HasPrefix(resource, "accesspoint/") || strings.HasPrefix(resource, "accesspoint:") {
+		a.Name = resource[len("accesspoint/"):]
+	}
+	if (a.Service != serviceName && a.Service != objectLambdaService) ||
+		!isHostLabel(a.AccountID) || !a.validName() {
+		return accessPointARN{}, fmt.Errorf("%w: %s", ErrInvalidARN, s)
+	}
+	return a, nil
+}
+
+// isGlobal reports whether the ARN is
+// of a multi-region access point.
+func (a accessPointARN) isGlobal() bool {
+	return a.Region == ""
+}
+
+// validName reports whether the name of the access point is valid,
+// which is <alias>.mrap for multi-region access points.
+func (a accessPointARN) validName() bool {
+	if !a.isGlobal() {
+		return isHostLabel(a.Name)
+	}
+	return a.Service == serviceName && strings.HasSuffix(a.Name, ".mrap") &&
+		isHostLabel(strings.TrimSuffix(a.Name, ".mrap"))
+}
+
+// isHostLabel reports whether s can be used in a DNS label.
+func isHostLabel(s string) bool {
+	if s == "" || len(s) > 63 || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for _, c := range s {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// host returns the host of the access point, eg.
+// <name>-<account>.s3-object-lambda.<region>.amazonaws.com,
+// or <alias>.mrap.accesspoint.s3-global.amazonaws.com
+// for multi-region access points.
+func (a accessPointARN) host(fips, dualStack bool) string {
+	if a.isGlobal() {
+		return a.Name + ".accesspoint.s3-global.amazonaws.com"
+	}
+
+	service := "s3-accesspoint"
+	if a.Service == objectLambdaService {
+		// Object Lambda has no dual-stack endpoints.
+		service, dualStack = objectLambdaService, false
+	}
+	if fips {
+		service += "-fips"
+	}
+	if dualStack {
+		service += ".dualstack"
+	}
+
+	domain := "amazonaws.com"
+	if a.Partition == "aws-cn" {
+		domain = "amazonaws.com.cn"
+	}
+	return a.Name + "-" + a.AccountID + "." + service + "." + a.Region + "." + domain
+}
+
+// accessPointURL returns the URL of the object key in the access
+// point given by its ARN, and the scope its requests are signed for:
+// the region and service of the ARN, or us-east-1 for multi-region
+// access points.
+func (s3 *S3) accessPointURL(arn, key string) (string, signingScope, error) {
+	a, err := parseAccessPointARN(arn)
+	if err != nil {
+		return "", signingScope{}, err
+	}
+	scope := signingScope{region: a.Region, service: a.Service}
+	if a.isGlobal() {
+		scope.region = globalRegion
+	}
+	return joinKey("https://"+a.host(s3.fips, s3.dualStack), key), scope, nil
+}
@@ -0,0 +1,133 @@
+package gos3
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestS3_getURL_ARN(t *testing.T) {
+	tests := []struct {
+		name   string
+		bucket string
+		fips   bool
+		want   string
+	}{
+		{"object lambda", "arn:aws:s3-object-lambda:us-west-2:123456789012:accesspoint/my-lambda", false, "https://my-lambda-123456789012.s3-object-lambda.us-west-2.amazonaws.com/test.txt"},
+		{"object lambda fips", "arn:aws:s3-object-lambda:us-east-2:123456789012:accesspoint/my-lambda", true, "https://my-lambda-123456789012.s3-object-lambda-fips.us-east-2.amazonaws.com/test.txt"},
+		{"access point", "arn:aws:s3:eu-west-1:123456789012:accesspoint:my-ap", false, "https://my-ap-123456789012.s3-accesspoint.eu-west-1.amazonaws.com/test.txt"},
+		{"china", "arn:aws-cn:s3:cn-north-1:123456789012:accesspoint/my-ap", false, "https://my-ap-123456789012.s3-accesspoint.cn-north-1.amazonaws.com.cn/test.txt"},
+		{"multi-region", "arn:aws:s3::123456789012:accesspoint/mfzwi23gnjvgw.mrap", false, "https://mfzwi23gnjvgw.mrap.accesspoint.s3-global.amazonaws.com/test.txt"},
+		{"invalid", "arn:aws:s3-object-lambda:us-west-2:123456789012:bucket/my-lambda", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+			s3.fips = tt.fips
+			if got := s3.getURL(tt.bucket, "test.txt"); got != tt.want {
+				t.Errorf("S3.getURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAccessPointARN(t *testing.T) {
+	for _, arn := range []string{
+		"arn:aws:s3-object-lambda:us-west-2:123456789012",
+		"arn:aws:s3-object-lambda::123456789012:accesspoint/my-lambda",
+		"arn:aws:lambda:us-west-2:123456789012:accesspoint/my-lambda",
+		"arn:aws:s3:us-west-2:123456789012:accesspoint/",
+		"arn:aws:s3:us-west-2:123456789012:accesspoint/my.lambda",
+		"arn::s3:us-west-2:123456789012:accesspoint/my-lambda",
+		"arn:aws:s3::123456789012:accesspoint/my-ap",
+		"arn:aws:s3-object-lambda::123456789012:accesspoint/mfzwi23gnjvgw.mrap",
+	} {
+		if _, err := parseAccessPointARN(arn); !errors.Is(err, ErrInvalidARN) {
+			t.Errorf("parseAccessPointARN(%q) error = %v, want ErrInvalidARN", arn, err)
+		}
+	}
+}
+
+func TestS3_FileDownload_ObjectLambda(t *testing.T) {
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Host != "my-lambda-123456789012.s3-object-lambda.us-west-2.amazonaws.com" ||
+			!strings.Contains(r.Header.Get("Authorization"), "/us-west-2/s3-object-lambda/aws4_request") {
+			t.Errorf("unexpected request to %s signed with %s", r.URL, r.Header.Get("Authorization"))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("transformed")),
+		}, nil
+	})})
+
+	body, err := s3.FileDownload(DownloadInput{
+		Bucket:    "arn:aws:s3-object-lambda:us-west-2:123456789012:accesspoint/my-lambda",
+		ObjectKey: "test.txt",
+	})
+	if err != nil {
+		t.Fatalf("S3.FileDownload() error = %v", err)
+	}
+	defer body.Close()
+	if data, _ := ioutil.ReadAll(body); string(data) != "transformed" {
+		t.Errorf("S3.FileDownload() = %s", data)
+	}
+
+	_, err = s3.FileDownload(DownloadInput{
+		Bucket:    "arn:aws:s3-object-lambda:us-west-2:accesspoint/my-lambda",
+		ObjectKey: "test.txt",
+	})
+	if !errors.Is(err, ErrInvalidARN) {
+		t.Errorf("S3.FileDownload() error = %v, want ErrInvalidARN", err)
+	}
+}
+
+func TestS3_FileDownload_MultiRegionAccessPoint(t *testing.T) {
+	s3 := New("eu-west-1", "AccessKey", "SuperSecretKey")
+	s3.SetClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Host != "mfzwi23gnjvgw.mrap.accesspoint.s3-global.amazonaws.com" ||
+			!strings.Contains(r.Header.Get("Authorization"), "/us-east-1/s3/aws4_request") {
+			t.Errorf("unexpected request to %s signed with %s", r.URL, r.Header.Get("Authorization"))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("replica")),
+		}, nil
+	})})
+
+	body, err := s3.FileDownload(DownloadInput{
+		Bucket:    "arn:aws:s3::123456789012:accesspoint/mfzwi23gnjvgw.mrap",
+		ObjectKey: "test.txt",
+	})
+	if err != nil {
+		t.Fatalf("S3.FileDownload() error = %v", err)
+	}
+	body.Close()
+}
+
+func TestS3_FileDownload_VirtualHostedScope(t *testing.T) {
+	// Buckets whose name looks like the host of another
+	// service are signed for S3 in the client's region.
+	for _, bucket := range []string{"sts", "my.s3-accesspoint.data", "my.s3-object-lambda.data"} {
+		s3 := New("eu-west-1", "AccessKey", "SuperSecretKey")
+		s3.SetURLStyle(VirtualHostedStyle)
+		s3.SetClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if r.URL.Host != bucket+".s3.eu-west-1.amazonaws.com" ||
+				!strings.Contains(r.Header.Get("Authorization"), "/eu-west-1/s3/aws4_request") {
+				t.Errorf("unexpected request to %s signed with %s", r.URL, r.Header.Get("Authorization"))
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+			}, nil
+		})})
+
+		body, err := s3.FileDownload(DownloadInput{Bucket: bucket, ObjectKey: "test.txt"})
+		if err != nil {
+			t.Fatalf("S3.FileDownload(%s) error = %v", bucket, err)
+		}
+		body.Close()
+	}
+}
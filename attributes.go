@@ -0,0 +1,67 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Attributes which can be requested from GetObjectAttributes.
+const (
+	ObjectAttributeETag         = "ETag"
+	ObjectAttributeChecksum     = "Checksum"
+	ObjectAttributeObjectParts  = "ObjectParts"
+	ObjectAttributeStorageClass = "StorageClass"
+	ObjectAttributeObjectSize   = "ObjectSize"
+)
+
+// ObjectAttributes is returned by GetObjectAttributes. Only
+// the requested attributes are set.
+type ObjectAttributes struct {
+	ETag         string         `xml:"ETag"`
+	Checksum     ObjectChecksum `xml:"Checksum"`
+	ObjectParts  ObjectParts    `xml:"ObjectParts"`
+	StorageClass string         `xml:"StorageClass"`
+	ObjectSize   int64          `xml:"ObjectSize"`
+}
+
+// ObjectChecksum holds the base64-encoded checksums
+// of an object uploaded with additional checksums.
+type ObjectChecksum struct {
+	CRC32  string `xml:"ChecksumCRC32"`
+	CRC32C string `xml:"ChecksumCRC32C"`
+	SHA1   string `xml:"ChecksumSHA1"`
+	SHA256 string `xml:"ChecksumSHA256"`
+}
+
+// ObjectParts describes the parts of
+// an object created by a multipart upload.
+type ObjectParts struct {
+	TotalPartsCount int `xml:"TotalPartsCount"`
+}
+
+// GetObjectAttributes makes a GET call to retrieve attrs, some of the
+// ObjectAttribute constants, of an object without downloading it.
+// All the attributes are requested if none are given. Unlike FileHead,
+// it returns the checksums and the number of parts of the object.
+func (s3 *S3) GetObjectAttributes(bucket, key string, attrs ...string) (ObjectAttributes, error) {
+	if len(attrs) == 0 {
+		attrs = []string{
+			ObjectAttributeETag,
+			ObjectAttributeChecksum,
+			ObjectAttributeObjectParts,
+			ObjectAttributeStorageClass,
+			ObjectAttributeObjectSize,
+		}
+	}
+	header := http.Header{}
+	header.Set("x-amz-object-attributes", strings.Join(attrs, ","))
+
+	var out ObjectAttributes
+	if err := s3.doXML(http.MethodGet, bucket, key, "?attributes", header, nil, &out); err != nil {
+		return ObjectAttributes{}, err
+	}
+	return out, nil
+}
@@ -0,0 +1,40 @@
+package gos3
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestS3_GetObjectAttributes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["attributes"]; !ok || r.Method != http.MethodGet {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if got := r.Header.Get("x-amz-object-attributes"); got != "ETag,ObjectParts,ObjectSize" {
+			t.Errorf("x-amz-object-attributes = %s", got)
+		}
+		w.Write([]byte(`<GetObjectAttributesResponse xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <ETag>8f5b4c1b9d3a2e7f-3</ETag>
+  <ObjectParts><TotalPartsCount>3</TotalPartsCount></ObjectParts>
+  <ObjectSize>15728640</ObjectSize>
+</GetObjectAttributesResponse>`))
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	got, err := s3.GetObjectAttributes("bucket", "video.mp4", ObjectAttributeETag, ObjectAttributeObjectParts, ObjectAttributeObjectSize)
+	if err != nil {
+		t.Fatalf("S3.GetObjectAttributes() error = %v", err)
+	}
+	want := ObjectAttributes{
+		ETag:        "8f5b4c1b9d3a2e7f-3",
+		ObjectParts: ObjectParts{TotalPartsCount: 3},
+		ObjectSize:  15728640,
+	}
+	if got != want {
+		t.Errorf("S3.GetObjectAttributes() = %+v, want %+v", got, want)
+	}
+}
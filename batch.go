@@ -0,0 +1,295 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// maxDeleteKeys is the maximum number of keys
+// accepted by a single DeleteObjects call.
+const maxDeleteKeys = 1000
+
+// BatchDeleteInput is passed to FileDeleteBatch as a parameter.
+type BatchDeleteInput struct {
+	Bucket string
+	Keys   []string
+
+	// Concurrency is the number of DeleteObjects calls
+	// in flight at once, defaults to 1.
+	Concurrency int
+}
+
+// BatchDeleteOutput is returned by FileDeleteBatch.
+type BatchDeleteOutput struct {
+	Deleted []string
+	Errors  []DeleteError
+}
+
+// DeleteError describes a key that could not be deleted.
+type DeleteError struct {
+	Key     string `xml:"Key"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+type deleteObject struct {
+	Key string `xml:"Key"`
+}
+
+// deleteRequest is sent to S3 to delete multiple objects.
+type deleteRequest struct {
+	XMLName xml.Name       `xml:"Delete"`
+	Quiet   bool           `xml:"Quiet"`
+	Objects []deleteObject `xml:"Object"`
+}
+
+// deleteResult receives the DeleteResult XML.
+type deleteResult struct {
+	Deleted []deleteObject `xml:"Deleted"`
+	Errors  []DeleteError  `xml:"Error"`
+}
+
+// FileDeleteBatch deletes multiple objects of a bucket using the
+// DeleteObjects API, sending one request per 1000 keys, up to
+// u.Concurrency at once. Keys which could not be deleted are reported
+// in the Errors of the output rather than as an error. If a request
+// fails as a whole, all of its keys are reported in the Errors and
+// the first such failure is also returned as the error, once all
+// the requests are done.
+func (s3 *S3) FileDeleteBatch(u BatchDeleteInput) (BatchDeleteOutput, error) {
+	concurrency := u.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var (
+		batches = make([]deleteResult, (len(u.Keys)+maxDeleteKeys-1)/maxDeleteKeys)
+		errs    = make([]error, len(batches))
+		sem     = make(chan struct{}, concurrency)
+		wg      sync.WaitGroup
+	)
+	for i := range batches {
+		start, end := i*maxDeleteKeys, (i+1)*maxDeleteKeys
+		if end > len(u.Keys) {
+			end = len(u.Keys)
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, keys []string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			batches[i], errs[i] = s3.deleteObjects(u.Bucket, keys)
+			if errs[i] != nil {
+				batches[i] = failedDeleteResult(keys, errs[i])
+			}
+		}(i, u.Keys[start:end])
+	}
+	wg.Wait()
+
+	var out BatchDeleteOutput
+	for _, result := range batches {
+		for _, d := range result.Deleted {
+			out.Deleted = append(out.Deleted, d.Key)
+		}
+		out.Errors = append(out.Errors, result.Errors...)
+	}
+	for _, err := range errs {
+		if err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+// failedDeleteResult reports every key of a
+// DeleteObjects call which failed with err.
+func failedDeleteResult(keys []string, err error) deleteResult {
+	var code string
+	var s3Err *S3Error
+	if errors.As(err, &s3Err) {
+		code = s3Err.Code
+	}
+
+	result := deleteResult{Errors: make([]DeleteError, len(keys))}
+	for i, key := range keys {
+		result.Errors[i] = DeleteError{Key: key, Code: code, Message: err.Error()}
+	}
+	return result
+}
+
+// deleteObjects makes a POST call to delete up to 1000 keys.
+func (s3 *S3) deleteObjects(bucket string, keys []string) (deleteResult, error) {
+	del := deleteRequest{Objects: make([]deleteObject, len(keys))}
+	for i, key := range keys {
+		del.Objects[i].Key = key
+	}
+	body, err := xml.Marshal(del)
+	if err != nil {
+		return deleteResult{}, err
+	}
+
+	req, err := s3.newRequest(
+		context.Background(), http.MethodPost, bucket, "", "?delete", bytes.NewReader(body),
+	)
+	if err != nil {
+		return deleteResult{}, err
+	}
+
+	// S3 requires the Content-MD5 header for this API.
+	sum := md5.Sum(body)
+	req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	req.Header.Set("Content-Type", "application/xml")
+
+	if err := s3.signRequestWithBody(req, body); err != nil {
+		return deleteResult{}, err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return deleteResult{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return deleteResult{}, newResponseError(res)
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return deleteResult{}, err
+	}
+
+	var result deleteResult
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return deleteResult{}, err
+	}
+	return result, nil
+}
+
+// BatchUploadInput is passed to BatchUpload as a parameter.
+type BatchUploadInput struct {
+	Files []UploadInput
+
+	// Concurrency is the number of files uploaded at once,
+	// defaults to 5.
+	Concurrency int
+}
+
+// BatchUploadResult is the outcome of the upload of one of
+// the files of a BatchUploadInput, either a response or an error.
+type BatchUploadResult struct {
+	Response UploadResponse
+	Err      error
+}
+
+// BatchUpload uploads in.Files using FilePut, up to in.Concurrency at
+// once. A failing file does not stop the others. The results are in the
+// same order as in.Files, and the returned error is set if any failed.
+func (s3 *S3) BatchUpload(in BatchUploadInput) ([]BatchUploadResult, error) {
+	concurrency := in.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	var (
+		results = make([]BatchUploadResult, len(in.Files))
+		sem     = make(chan struct{}, concurrency)
+		wg      sync.WaitGroup
+	)
+	for i, u := range in.Files {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, u UploadInput) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			resp, err := s3.FilePut(u)
+			results[i] = BatchUploadResult{Response: resp, Err: err}
+		}(i, u)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d uploads failed", failed, len(results))
+	}
+	return results, nil
+}
+
+// BatchTagInput is passed to BatchTagObjects as a parameter.
+type BatchTagInput struct {
+	Bucket string
+	Prefix string
+	Tags   map[string]string
+
+	// Concurrency is the number of objects tagged at once,
+	// defaults to 5.
+	Concurrency int
+}
+
+// BatchTagObjects lists the objects of in.Bucket whose key starts with
+// in.Prefix and replaces their tags with in.Tags using PutObjectTagging,
+// up to in.Concurrency objects at once, as they are listed. A failing
+// object does not stop the others. It returns the number of objects
+// tagged, along with the error of each object which was not, prefixed
+// with its key, and the error of the listing if it failed.
+func (s3 *S3) BatchTagObjects(in BatchTagInput) (int, []error) {
+	concurrency := in.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	var (
+		tagged int
+		errs   []error
+		mu     sync.Mutex
+		sem    = make(chan struct{}, concurrency)
+		wg     sync.WaitGroup
+	)
+	objects, listErr := s3.StreamListObjects(context.Background(), ListInput{Bucket: in.Bucket, Prefix: in.Prefix})
+	for obj := range objects {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(key string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			err := s3.PutObjectTagging(in.Bucket, key, in.Tags)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			tagged++
+		}(obj.Key)
+	}
+	wg.Wait()
+
+	if err := <-listErr; err != nil {
+		errs = append(errs, err)
+	}
+	return tagged, errs
+}
@@ -0,0 +1,228 @@
+package gos3
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestS3_FileDeleteBatch(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["delete"]; r.Method != http.MethodPost || !ok {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		sum := md5.Sum(body)
+		if r.Header.Get("Content-MD5") != base64.StdEncoding.EncodeToString(sum[:]) {
+			t.Errorf("invalid Content-MD5 header")
+		}
+
+		var del deleteRequest
+		if err := xml.Unmarshal(body, &del); err != nil {
+			t.Fatal(err)
+		}
+		if len(del.Objects) > maxDeleteKeys {
+			t.Errorf("got %d keys in a single request", len(del.Objects))
+		}
+		mu.Lock()
+		requests++
+		mu.Unlock()
+
+		var b strings.Builder
+		b.WriteString("<DeleteResult>")
+		for _, o := range del.Objects {
+			if o.Key == "locked" {
+				b.WriteString("<Error><Key>locked</Key><Code>AccessDenied</Code><Message>Access Denied</Message></Error>")
+				continue
+			}
+			fmt.Fprintf(&b, "<Deleted><Key>%s</Key></Deleted>", o.Key)
+		}
+		b.WriteString("</DeleteResult>")
+		w.Write([]byte(b.String()))
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	keys := []string{"locked"}
+	for i := 0; i < 1500; i++ {
+		keys = append(keys, fmt.Sprintf("key-%d", i))
+	}
+	out, err := s3.FileDeleteBatch(BatchDeleteInput{Bucket: "bucket", Keys: keys})
+	if err != nil {
+		t.Fatalf("S3.FileDeleteBatch() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("S3.FileDeleteBatch() made %d requests, want 2", requests)
+	}
+	if len(out.Deleted) != 1500 {
+		t.Errorf("S3.FileDeleteBatch() deleted %d keys, want 1500", len(out.Deleted))
+	}
+	if len(out.Errors) != 1 || out.Errors[0].Key != "locked" || out.Errors[0].Code != "AccessDenied" {
+		t.Errorf("S3.FileDeleteBatch() errors = %+v", out.Errors)
+	}
+}
+
+func TestS3_FileDeleteBatch_Concurrency(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var del deleteRequest
+		if err := xml.Unmarshal(body, &del); err != nil {
+			t.Fatal(err)
+		}
+		if del.Objects[0].Key == "key-1000" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("<Error><Code>SlowDown</Code><Message>Please reduce your request rate.</Message></Error>"))
+			return
+		}
+
+		var b strings.Builder
+		b.WriteString("<DeleteResult>")
+		for _, o := range del.Objects {
+			fmt.Fprintf(&b, "<Deleted><Key>%s</Key></Deleted>", o.Key)
+		}
+		b.WriteString("</DeleteResult>")
+		w.Write([]byte(b.String()))
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	var keys []string
+	for i := 0; i < 3500; i++ {
+		keys = append(keys, fmt.Sprintf("key-%d", i))
+	}
+	out, err := s3.FileDeleteBatch(BatchDeleteInput{Bucket: "bucket", Keys: keys, Concurrency: 3})
+	if err == nil {
+		t.Errorf("S3.FileDeleteBatch() expected an error for the second batch")
+	}
+	if len(out.Deleted) != 2500 || out.Deleted[0] != "key-0" || out.Deleted[1000] != "key-2000" {
+		t.Errorf("S3.FileDeleteBatch() deleted %d keys", len(out.Deleted))
+	}
+	if len(out.Errors) != 1000 || out.Errors[0].Key != "key-1000" || out.Errors[0].Code != "SlowDown" {
+		t.Errorf("S3.FileDeleteBatch() reported %d errors", len(out.Errors))
+	}
+}
+
+func TestS3_BatchUpload(t *testing.T) {
+	var (
+		mu            sync.Mutex
+		inFlight, max int
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > max {
+			max = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		if strings.HasSuffix(r.URL.Path, "/bad.txt") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		ioutil.ReadAll(r.Body)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	var files []UploadInput
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("%d.txt", i)
+		if i == 3 {
+			key = "bad.txt"
+		}
+		files = append(files, UploadInput{Bucket: "bucket", ObjectKey: key, Body: strings.NewReader("hello")})
+	}
+
+	results, err := s3.BatchUpload(BatchUploadInput{Files: files, Concurrency: 2})
+	if err == nil {
+		t.Errorf("S3.BatchUpload() expected an error for bad.txt")
+	}
+	if len(results) != len(files) {
+		t.Fatalf("S3.BatchUpload() returned %d results, want %d", len(results), len(files))
+	}
+	for i, r := range results {
+		if (r.Err != nil) != (i == 3) {
+			t.Errorf("result %d error = %v", i, r.Err)
+		}
+	}
+	if max > 2 {
+		t.Errorf("%d uploads in flight, want at most 2", max)
+	}
+}
+
+func TestS3_BatchTagObjects(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		tagged = map[string]string{}
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if q := r.URL.Query(); q.Get("list-type") != "2" || q.Get("prefix") != "team-a/" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			io.WriteString(w, `<ListBucketResult><IsTruncated>false</IsTruncated>`)
+			for i := 0; i < 10; i++ {
+				fmt.Fprintf(w, `<Contents><Key>team-a/%d.txt</Key></Contents>`, i)
+			}
+			io.WriteString(w, `</ListBucketResult>`)
+			return
+		}
+		if _, ok := r.URL.Query()["tagging"]; r.Method != http.MethodPut || !ok {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if r.URL.Path == "/bucket/team-a/7.txt" {
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `<Error><Code>AccessDenied</Code></Error>`)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		tagged[r.URL.Path] = string(body)
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	n, errs := s3.BatchTagObjects(BatchTagInput{
+		Bucket:      "bucket",
+		Prefix:      "team-a/",
+		Tags:        map[string]string{"cost-center": "b"},
+		Concurrency: 3,
+	})
+	if n != 9 || len(tagged) != 9 {
+		t.Errorf("S3.BatchTagObjects() tagged %d objects, server got %d, want 9", n, len(tagged))
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrAccessDenied) || !strings.Contains(errs[0].Error(), "team-a/7.txt") {
+		t.Errorf("S3.BatchTagObjects() errors = %v", errs)
+	}
+	want := `<Tagging><TagSet><Tag><Key>cost-center</Key><Value>b</Value></Tag></TagSet></Tagging>`
+	if got := tagged["/bucket/team-a/0.txt"]; got != want {
+		t.Errorf("S3.BatchTagObjects() sent = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// globalEndpoint answers the calls which are not
+// made to a bucket, such as ListBuckets.
+const globalEndpoint = "https://s3.amazonaws.com"
+
+// CreateBucketInput is passed to CreateBucket as a parameter.
+type CreateBucketInput struct {
+	Bucket string
+
+	// Region is where the bucket is created, defaults
+	// to the region the client was created with.
+	Region string
+	// ACL is a canned ACL such as "private" or "public-read".
+	ACL string
+}
+
+// createBucketConfiguration is sent to S3 to create
+// a bucket outside of us-east-1.
+type createBucketConfiguration struct {
+	XMLName            xml.Name `xml:"CreateBucketConfiguration"`
+	LocationConstraint string   `xml:"LocationConstraint"`
+}
+
+// locationConstraint receives the response of GetBucketLocation.
+type locationConstraint struct {
+	Location string `xml:",chardata"`
+}
+
+// CreateBucket makes a PUT call to create a bucket.
+func (s3 *S3) CreateBucket(in CreateBucketInput) error {
+	region := in.Region
+	if region == "" {
+		region = s3.Region
+	}
+
+	header := http.Header{}
+	if in.ACL != "" {
+		header.Set("x-amz-acl", in.ACL)
+	}
+
+	// us-east-1 is the default location, and S3 rejects
+	// requests which name it as a location constraint.
+	if region == "" || region == "us-east-1" {
+		return s3.doXML(http.MethodPut, in.Bucket, "", "", header, nil, nil)
+	}
+	return s3.doXML(http.MethodPut, in.Bucket, "", "", header, createBucketConfiguration{
+		LocationConstraint: region,
+	}, nil)
+}
+
+// DeleteBucket makes a DELETE call to delete a bucket,
+// which must be empty.
+func (s3 *S3) DeleteBucket(bucket string) error {
+	return s3.doXML(http.MethodDelete, bucket, "", "", nil, nil, nil)
+}
+
+// BucketExists makes a HEAD call to check whether a bucket exists
+// and is accessible with the credentials of the client. It returns
+// false for a missing bucket and an error for any other failure,
+// such as a 403 for a bucket owned by someone else.
+func (s3 *S3) BucketExists(bucket string) (bool, error) {
+	return s3.exists(bucket, "")
+}
+
+// GetBucketLocation makes a GET call to find the region of a bucket.
+// Unless a custom endpoint is set, the call is made to us-east-1,
+// which answers for buckets in every region, so it works even when
+// the client was created with the wrong region.
+func (s3 *S3) GetBucketLocation(bucket string) (string, error) {
+	client := s3
+	if s3.Endpoint == "" {
+		client = s3.withRegion("us-east-1")
+	}
+
+	req, err := client.newRequest(context.Background(), http.MethodGet, bucket, "", "?location", nil)
+	if err != nil {
+		return "", err
+	}
+
+	if err := client.signRequest(req); err != nil {
+		return "", err
+	}
+
+	res, err := client.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		// Redirects and some errors name the
+		// region of the bucket in a header.
+		if region := res.Header.Get("x-amz-bucket-region"); region != "" {
+			return region, nil
+		}
+		return "", newResponseError(res)
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var result locationConstraint
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return "", err
+	}
+	// Buckets in us-east-1 have an empty location constraint.
+	if result.Location == "" {
+		return "us-east-1", nil
+	}
+	return result.Location, nil
+}
+
+// BucketInfo is a bucket returned by ListBuckets.
+type BucketInfo struct {
+	Name         string    `xml:"Name"`
+	CreationDate time.Time `xml:"CreationDate"`
+}
+
+// listAllMyBucketsResult is returned by S3 for ListBuckets.
+type listAllMyBucketsResult struct {
+	Buckets []BucketInfo `xml:"Buckets>Bucket"`
+}
+
+// ListBuckets makes a GET call to list all the buckets
+// owned by the account of the client. Unless a custom endpoint
+// is set, the call is made to the global endpoint, s3.amazonaws.com,
+// and signed for us-east-1.
+func (s3 *S3) ListBuckets() ([]BucketInfo, error) {
+	region, endpoint := s3.Region, s3.Endpoint
+	if endpoint == "" {
+		region, endpoint = "us-east-1", globalEndpoint
+	}
+	client := s3.withRegion(region)
+	client.urlBuilder = PathStyleURLBuilder{Endpoint: endpoint}
+
+	var result listAllMyBucketsResult
+	if err := client.doXML(http.MethodGet, "", "", "", nil, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Buckets, nil
+}
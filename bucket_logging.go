@@ -0,0 +1,50 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// LoggingConfig is the server access logging configuration of a
+// bucket. The logs of every request to the bucket are delivered
+// to TargetBucket, under keys starting with TargetPrefix.
+type LoggingConfig struct {
+	TargetBucket string  `xml:"TargetBucket"`
+	TargetPrefix string  `xml:"TargetPrefix"`
+	TargetGrants []Grant `xml:"TargetGrants>Grant,omitempty"`
+}
+
+// bucketLoggingStatus is the XML representation of a LoggingConfig,
+// LoggingEnabled is omitted when logging is disabled.
+type bucketLoggingStatus struct {
+	XMLName        xml.Name       `xml:"BucketLoggingStatus"`
+	LoggingEnabled *LoggingConfig `xml:"LoggingEnabled,omitempty"`
+}
+
+// PutBucketLogging makes a PUT call to replace the server access
+// logging configuration of a bucket with cfg. A cfg without
+// a TargetBucket disables logging.
+func (s3 *S3) PutBucketLogging(bucket string, cfg LoggingConfig) error {
+	var status bucketLoggingStatus
+	if cfg.TargetBucket != "" {
+		status.LoggingEnabled = &cfg
+	}
+	return s3.doXML(http.MethodPut, bucket, "", "?logging", nil, status, nil)
+}
+
+// GetBucketLogging makes a GET call and returns the server access
+// logging configuration of a bucket, which has no TargetBucket
+// if logging is disabled.
+func (s3 *S3) GetBucketLogging(bucket string) (LoggingConfig, error) {
+	var status bucketLoggingStatus
+	if err := s3.doXML(http.MethodGet, bucket, "", "?logging", nil, nil, &status); err != nil {
+		return LoggingConfig{}, err
+	}
+	if status.LoggingEnabled == nil {
+		return LoggingConfig{}, nil
+	}
+	return *status.LoggingEnabled, nil
+}
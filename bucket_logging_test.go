@@ -0,0 +1,65 @@
+package gos3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestS3_BucketLogging(t *testing.T) {
+	var stored []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["logging"]; !ok || r.URL.Path != "/bucket" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			stored, _ = ioutil.ReadAll(r.Body)
+		case http.MethodGet:
+			w.Write(stored)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	cfg := LoggingConfig{
+		TargetBucket: "logs",
+		TargetPrefix: "bucket/",
+		TargetGrants: []Grant{{
+			Grantee:    Grantee{Type: GranteeEmail, EmailAddress: "ops@example.com"},
+			Permission: "READ",
+		}},
+	}
+	if err := s3.PutBucketLogging("bucket", cfg); err != nil {
+		t.Fatalf("S3.PutBucketLogging() error = %v", err)
+	}
+	want := `<BucketLoggingStatus><LoggingEnabled><TargetBucket>logs</TargetBucket><TargetPrefix>bucket/</TargetPrefix>` +
+		`<TargetGrants><Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="AmazonCustomerByEmail">` +
+		`<EmailAddress>ops@example.com</EmailAddress></Grantee><Permission>READ</Permission></Grant></TargetGrants>` +
+		`</LoggingEnabled></BucketLoggingStatus>`
+	if string(stored) != want {
+		t.Errorf("S3.PutBucketLogging() sent = %s, want %s", stored, want)
+	}
+
+	got, err := s3.GetBucketLogging("bucket")
+	if err != nil {
+		t.Fatalf("S3.GetBucketLogging() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("S3.GetBucketLogging() = %+v, want %+v", got, cfg)
+	}
+
+	if err := s3.PutBucketLogging("bucket", LoggingConfig{}); err != nil {
+		t.Fatalf("S3.PutBucketLogging() error = %v", err)
+	}
+	if want := `<BucketLoggingStatus></BucketLoggingStatus>`; string(stored) != want {
+		t.Errorf("S3.PutBucketLogging() sent = %s, want %s", stored, want)
+	}
+	if got, err := s3.GetBucketLogging("bucket"); err != nil || got.TargetBucket != "" {
+		t.Errorf("S3.GetBucketLogging() = %+v, %v, want logging disabled", got, err)
+	}
+}
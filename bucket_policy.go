@@ -0,0 +1,73 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// PutBucketPolicy makes a PUT call to replace the access
+// policy of a bucket with the JSON document policyJSON.
+func (s3 *S3) PutBucketPolicy(bucket, policyJSON string) error {
+	req, err := s3.newRequest(
+		context.Background(), http.MethodPut, bucket, "", "?policy", strings.NewReader(policyJSON),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if err := s3.signRequestWithBody(req, []byte(policyJSON)); err != nil {
+		return err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 && res.StatusCode != 204 {
+		return newResponseError(res)
+	}
+	return nil
+}
+
+// GetBucketPolicy makes a GET call and returns the access policy
+// of a bucket as a JSON document. If the bucket has no policy,
+// the returned error matches ErrNotFound.
+func (s3 *S3) GetBucketPolicy(bucket string) (string, error) {
+	req, err := s3.newRequest(context.Background(), http.MethodGet, bucket, "", "?policy", nil)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s3.signRequest(req); err != nil {
+		return "", err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return "", newResponseError(res)
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// DeleteBucketPolicy makes a DELETE call to remove the access policy of a bucket.
+func (s3 *S3) DeleteBucketPolicy(bucket string) error {
+	return s3.doXML(http.MethodDelete, bucket, "", "?policy", nil, nil, nil)
+}
@@ -0,0 +1,61 @@
+package gos3
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestS3_BucketPolicy(t *testing.T) {
+	var stored []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["policy"]; !ok || r.URL.Path != "/bucket" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			stored, _ = ioutil.ReadAll(r.Body)
+			sum := sha256.Sum256(stored)
+			if got := r.Header.Get("x-amz-content-sha256"); got != hex.EncodeToString(sum[:]) {
+				t.Errorf("x-amz-content-sha256 = %s", got)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(stored)
+		case http.MethodDelete:
+			stored = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	policy := `{"Version":"2012-10-17","Statement":[]}`
+	if err := s3.PutBucketPolicy("bucket", policy); err != nil {
+		t.Fatalf("S3.PutBucketPolicy() error = %v", err)
+	}
+
+	got, err := s3.GetBucketPolicy("bucket")
+	if err != nil {
+		t.Fatalf("S3.GetBucketPolicy() error = %v", err)
+	}
+	if got != policy {
+		t.Errorf("S3.GetBucketPolicy() = %s, want %s", got, policy)
+	}
+
+	if err := s3.DeleteBucketPolicy("bucket"); err != nil {
+		t.Fatalf("S3.DeleteBucketPolicy() error = %v", err)
+	}
+	if _, err := s3.GetBucketPolicy("bucket"); err == nil {
+		t.Errorf("S3.GetBucketPolicy() expected an error after delete")
+	}
+}
@@ -0,0 +1,204 @@
+package gos3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestS3_CreateBucket(t *testing.T) {
+	tests := []struct {
+		name string
+		in   CreateBucketInput
+		want string
+	}{
+		{"default region", CreateBucketInput{Bucket: "bucket"}, ""},
+		{"other region", CreateBucketInput{Bucket: "bucket", Region: "eu-west-1", ACL: "private"}, "<LocationConstraint>eu-west-1</LocationConstraint>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut || r.URL.Path != "/bucket" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL)
+				}
+				if r.Header.Get("x-amz-acl") != tt.in.ACL {
+					t.Errorf("x-amz-acl = %q, want %q", r.Header.Get("x-amz-acl"), tt.in.ACL)
+				}
+				body, _ := ioutil.ReadAll(r.Body)
+				if tt.want == "" && len(body) > 0 || !strings.Contains(string(body), tt.want) {
+					t.Errorf("unexpected body %s", body)
+				}
+			}))
+			defer ts.Close()
+
+			s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+			s3.SetEndpoint(ts.URL)
+
+			if err := s3.CreateBucket(tt.in); err != nil {
+				t.Errorf("S3.CreateBucket() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestS3_DeleteBucket(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/bucket" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	if err := s3.DeleteBucket("bucket"); err != nil {
+		t.Errorf("S3.DeleteBucket() error = %v", err)
+	}
+}
+
+func TestS3_BucketExists(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bucket":
+			w.WriteHeader(http.StatusOK)
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	tests := []struct {
+		bucket  string
+		want    bool
+		wantErr bool
+	}{
+		{"bucket", true, false},
+		{"missing", false, false},
+		{"forbidden", false, true},
+	}
+	for _, tt := range tests {
+		got, err := s3.BucketExists(tt.bucket)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("S3.BucketExists(%s) error = %v, wantErr %v", tt.bucket, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("S3.BucketExists(%s) = %v, want %v", tt.bucket, got, tt.want)
+		}
+	}
+}
+
+func TestS3_GetBucketLocation(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; !ok {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/eu":
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">eu-west-1</LocationConstraint>`))
+		case "/us":
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"/>`))
+		default:
+			w.Header().Set("x-amz-bucket-region", "ap-south-1")
+			w.WriteHeader(http.StatusMovedPermanently)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-west-2", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	for bucket, want := range map[string]string{"eu": "eu-west-1", "us": "us-east-1", "moved": "ap-south-1"} {
+		got, err := s3.GetBucketLocation(bucket)
+		if err != nil {
+			t.Errorf("S3.GetBucketLocation(%s) error = %v", bucket, err)
+		}
+		if got != want {
+			t.Errorf("S3.GetBucketLocation(%s) = %s, want %s", bucket, got, want)
+		}
+	}
+}
+
+func TestS3_GetBucketLocation_GlobalEndpoint(t *testing.T) {
+	s3 := New("us-west-2", "AccessKey", "SuperSecretKey")
+	s3.SetClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Host != "s3.us-east-1.amazonaws.com" || !strings.Contains(r.Header.Get("Authorization"), "/us-east-1/s3/") {
+			t.Errorf("unexpected request to %s signed with %s", r.URL, r.Header.Get("Authorization"))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`<LocationConstraint>us-west-2</LocationConstraint>`)),
+		}, nil
+	})})
+
+	got, err := s3.GetBucketLocation("bucket")
+	if err != nil || got != "us-west-2" {
+		t.Errorf("S3.GetBucketLocation() = %s, %v", got, err)
+	}
+}
+
+func TestS3_ListBuckets(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		w.Write([]byte(`<ListAllMyBucketsResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Owner><ID>owner</ID><DisplayName>owner</DisplayName></Owner>
+  <Buckets>
+    <Bucket><Name>first</Name><CreationDate>2019-12-11T23:32:47.000Z</CreationDate></Bucket>
+    <Bucket><Name>second</Name><CreationDate>2020-01-02T03:04:05.000Z</CreationDate></Bucket>
+  </Buckets>
+</ListAllMyBucketsResult>`))
+	}))
+	defer ts.Close()
+
+	s3 := New("us-west-2", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	got, err := s3.ListBuckets()
+	if err != nil {
+		t.Fatalf("S3.ListBuckets() error = %v", err)
+	}
+	want := []BucketInfo{
+		{Name: "first", CreationDate: time.Date(2019, 12, 11, 23, 32, 47, 0, time.UTC)},
+		{Name: "second", CreationDate: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("S3.ListBuckets() = %v, want %v", got, want)
+	}
+}
+
+func TestS3_ListBuckets_GlobalEndpoint(t *testing.T) {
+	s3 := New("us-west-2", "AccessKey", "SuperSecretKey")
+	s3.SetClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.String() != "https://s3.amazonaws.com/" || !strings.Contains(r.Header.Get("Authorization"), "/us-east-1/s3/") {
+			t.Errorf("unexpected request to %s signed with %s", r.URL, r.Header.Get("Authorization"))
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`<ListAllMyBucketsResult><Buckets/></ListAllMyBucketsResult>`)),
+		}, nil
+	})})
+
+	if got, err := s3.ListBuckets(); err != nil || len(got) != 0 {
+		t.Errorf("S3.ListBuckets() = %v, %v", got, err)
+	}
+}
+
+// roundTripFunc lets tests answer requests made
+// to AWS hosts without a network connection.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,116 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// CredentialProvider retrieves the credentials used to sign requests.
+// token is empty for long-term credentials.
+type CredentialProvider interface {
+	Retrieve() (accessKey, secretKey, token string, err error)
+}
+
+// EnvCredentialProvider retrieves the credentials from the
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+// environment variables.
+type EnvCredentialProvider struct{}
+
+// Retrieve implements CredentialProvider.
+func (EnvCredentialProvider) Retrieve() (string, string, string, error) {
+	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+	if accessKey == "" {
+		return "", "", "", errors.New("AWS_ACCESS_KEY_ID is not set")
+	}
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if secretKey == "" {
+		return "", "", "", errors.New("AWS_SECRET_ACCESS_KEY is not set")
+	}
+	return accessKey, secretKey, os.Getenv("AWS_SESSION_TOKEN"), nil
+}
+
+// SharedFileCredentialProvider retrieves the credentials of Profile
+// from the shared credentials file, see NewFromProfile.
+type SharedFileCredentialProvider struct {
+	// Profile defaults to AWS_PROFILE, or else "default".
+	Profile string
+}
+
+// Retrieve implements CredentialProvider.
+func (p SharedFileCredentialProvider) Retrieve() (string, string, string, error) {
+	section, err := loadProfile(profileName(p.Profile))
+	if err != nil {
+		return "", "", "", err
+	}
+	return section["aws_access_key_id"], section["aws_secret_access_key"], section["aws_session_token"], nil
+}
+
+// IAMCredentialProvider retrieves the credentials of the IAM role
+// attached to the EC2 instance, see NewUsingIAM.
+type IAMCredentialProvider struct {
+	baseURL string
+}
+
+// Retrieve implements CredentialProvider.
+func (p IAMCredentialProvider) Retrieve() (string, string, string, error) {
+	baseURL := p.baseURL
+	if baseURL == "" {
+		baseURL = securityCredentialsURL
+	}
+	jsonResp, err := fetchIAMCredentials(baseURL)
+	if err != nil {
+		return "", "", "", err
+	}
+	return jsonResp.AccessKeyID, jsonResp.SecretAccessKey, jsonResp.Token, nil
+}
+
+// ECSCredentialProvider retrieves the credentials of the IAM role
+// of the ECS task, see NewUsingECSTaskRole.
+type ECSCredentialProvider struct {
+	host string
+}
+
+// Retrieve implements CredentialProvider.
+func (p ECSCredentialProvider) Retrieve() (string, string, string, error) {
+	host := p.host
+	if host == "" {
+		host = ecsCredentialsHost
+	}
+	jsonResp, err := fetchECSCredentials(host)
+	if err != nil {
+		return "", "", "", err
+	}
+	return jsonResp.AccessKeyID, jsonResp.SecretAccessKey, jsonResp.Token, nil
+}
+
+// NewWithCredentialChain returns an instance of S3 using the credentials
+// of the first of providers to succeed. Without providers, the
+// environment, the shared credentials file, the ECS task role and
+// the EC2 instance role are tried in that order.
+func NewWithCredentialChain(region string, providers ...CredentialProvider) (*S3, error) {
+	if len(providers) == 0 {
+		providers = []CredentialProvider{
+			EnvCredentialProvider{},
+			SharedFileCredentialProvider{},
+			ECSCredentialProvider{},
+			IAMCredentialProvider{},
+		}
+	}
+
+	var errs []string
+	for _, p := range providers {
+		accessKey, secretKey, token, err := p.Retrieve()
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		s3 := New(region, accessKey, secretKey)
+		s3.SetToken(token)
+		return s3, nil
+	}
+	return nil, errors.New("no credentials found: " + strings.Join(errs, "; "))
+}
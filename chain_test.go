@@ -0,0 +1,74 @@
+package gos3
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type staticProvider struct {
+	accessKey string
+	err       error
+}
+
+func (p staticProvider) Retrieve() (string, string, string, error) {
+	return p.accessKey, "sk", "", p.err
+}
+
+func TestNewWithCredentialChain(t *testing.T) {
+	s3, err := NewWithCredentialChain("us-east-1",
+		staticProvider{err: errors.New("first failed")},
+		staticProvider{accessKey: "second"},
+		staticProvider{accessKey: "third"},
+	)
+	if err != nil {
+		t.Fatalf("NewWithCredentialChain() error = %v", err)
+	}
+	if s3.AccessKey != "second" || s3.Region != "us-east-1" {
+		t.Errorf("NewWithCredentialChain() = %+v", s3)
+	}
+
+	_, err = NewWithCredentialChain("us-east-1",
+		staticProvider{err: errors.New("first failed")},
+		staticProvider{err: errors.New("second failed")},
+	)
+	if err == nil || !strings.Contains(err.Error(), "first failed; second failed") {
+		t.Errorf("NewWithCredentialChain() error = %v", err)
+	}
+}
+
+func TestCredentialProviders(t *testing.T) {
+	defer setenv("AWS_ACCESS_KEY_ID", "")()
+	defer setenv("AWS_SECRET_ACCESS_KEY", "")()
+	defer setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")()
+	defer setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", "")()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			w.Write([]byte("role"))
+			return
+		}
+		w.Write([]byte(`{"AccessKeyId":"iam","SecretAccessKey":"sk","Token":"token"}`))
+	}))
+	defer ts.Close()
+
+	s3, err := NewWithCredentialChain("us-east-1",
+		EnvCredentialProvider{},
+		ECSCredentialProvider{host: ts.URL},
+		IAMCredentialProvider{baseURL: ts.URL},
+	)
+	if err != nil {
+		t.Fatalf("NewWithCredentialChain() error = %v", err)
+	}
+	if s3.AccessKey != "iam" || s3.Token != "token" {
+		t.Errorf("NewWithCredentialChain() = %+v", s3)
+	}
+
+	defer setenv("AWS_ACCESS_KEY_ID", "env")()
+	defer setenv("AWS_SECRET_ACCESS_KEY", "sk")()
+	if accessKey, _, _, err := (EnvCredentialProvider{}).Retrieve(); accessKey != "env" || err != nil {
+		t.Errorf("EnvCredentialProvider.Retrieve() = %s, %v", accessKey, err)
+	}
+}
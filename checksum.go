@@ -0,0 +1,110 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Checksum algorithms accepted by UploadInput.Checksum.
+const (
+	ChecksumCRC32  = "CRC32"
+	ChecksumCRC32C = "CRC32C"
+	ChecksumSHA1   = "SHA1"
+	ChecksumSHA256 = "SHA256"
+)
+
+// checksumAlgorithms lists the algorithms in the
+// order their headers are looked up in a response.
+var checksumAlgorithms = []string{ChecksumCRC32C, ChecksumCRC32, ChecksumSHA256, ChecksumSHA1}
+
+// newChecksumHash returns a hash computing
+// the checksum of the given algorithm.
+func newChecksumHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case ChecksumCRC32:
+		return crc32.NewIEEE(), nil
+	case ChecksumCRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli)), nil
+	case ChecksumSHA1:
+		return sha1.New(), nil
+	case ChecksumSHA256:
+		return sha256.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown checksum %q", algorithm)
+	}
+}
+
+// checksumHeader returns the header carrying
+// the checksum of the given algorithm.
+func checksumHeader(algorithm string) string {
+	return "x-amz-checksum-" + strings.ToLower(algorithm)
+}
+
+// hashBody returns the base64-encoded sum of the body from start
+// to its end, and seeks the body back to start.
+func hashBody(body io.ReadSeeker, start int64, h hash.Hash) (string, error) {
+	if _, err := io.Copy(h, body); err != nil {
+		return "", err
+	}
+	if _, err := body.Seek(start, io.SeekStart); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// partChecksum returns the base64-encoded checksum of a part of
+// the upload, or an empty string if the upload has no Checksum.
+func (u UploadInput) partChecksum(part []byte) string {
+	if u.Checksum == "" {
+		return ""
+	}
+	h, _ := newChecksumHash(u.Checksum)
+	h.Write(part)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// checksumReader checks the body of a response against
+// its checksum once it has been read until EOF.
+type checksumReader struct {
+	io.ReadCloser
+	hash     hash.Hash
+	expected string
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF && base64.StdEncoding.EncodeToString(r.hash.Sum(nil)) != r.expected {
+		return n, ErrChecksumMismatch
+	}
+	return n, err
+}
+
+// verifyChecksum wraps the body of res to check it against the
+// first checksum found in its headers. Checksums of multipart
+// uploads, which are computed over the checksums of the parts
+// rather than over the object, are not verified.
+func verifyChecksum(res *http.Response) {
+	for _, algorithm := range checksumAlgorithms {
+		expected := res.Header.Get(checksumHeader(algorithm))
+		if expected == "" {
+			continue
+		}
+		if strings.Contains(expected, "-") {
+			return
+		}
+		h, _ := newChecksumHash(algorithm)
+		res.Body = &checksumReader{ReadCloser: res.Body, hash: h, expected: expected}
+		return
+	}
+}
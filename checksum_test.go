@@ -0,0 +1,148 @@
+package gos3
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestS3_FilePut_Checksum(t *testing.T) {
+	tests := []struct {
+		checksum string
+		header   string
+		want     string
+	}{
+		{ChecksumCRC32, "x-amz-checksum-crc32", "DUoRhQ=="},
+		{ChecksumCRC32C, "x-amz-checksum-crc32c", "yZRlqg=="},
+		{ChecksumSHA1, "x-amz-checksum-sha1", "Kq5sNclPz7QV2+lfQIuc6R7oRu0="},
+		{ChecksumSHA256, "x-amz-checksum-sha256", "uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek="},
+	}
+	for _, tt := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			if got := r.Header.Get(tt.header); got != tt.want || string(body) != "hello world" {
+				t.Errorf("%s: %s = %s for body %q, want %s", tt.checksum, tt.header, got, body, tt.want)
+			}
+		}))
+
+		s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+		s3.SetEndpoint(ts.URL)
+
+		_, err := s3.FilePut(UploadInput{
+			Bucket:    "bucket",
+			ObjectKey: "test.txt",
+			Checksum:  tt.checksum,
+			Body:      strings.NewReader("hello world"),
+		})
+		if err != nil {
+			t.Errorf("%s: S3.FilePut() error = %v", tt.checksum, err)
+		}
+		ts.Close()
+	}
+}
+
+func TestS3_MultipartUpload_Checksum(t *testing.T) {
+	m := &multipartServer{t: t, parts: map[string][]byte{}}
+	sums := map[int]string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		switch _, uploads := q["uploads"]; {
+		case uploads:
+			if got := r.Header.Get("x-amz-checksum-algorithm"); got != ChecksumSHA256 {
+				t.Errorf("x-amz-checksum-algorithm = %q, want %s", got, ChecksumSHA256)
+			}
+		case r.Method == http.MethodPut:
+			sum := sha256.Sum256(body)
+			if got, want := r.Header.Get("x-amz-checksum-sha256"), base64.StdEncoding.EncodeToString(sum[:]); got != want {
+				t.Errorf("part %s: x-amz-checksum-sha256 = %q, want %q", q.Get("partNumber"), got, want)
+			}
+			sums[len(sums)+1] = r.Header.Get("x-amz-checksum-sha256")
+		case r.Method == http.MethodPost:
+			var c completeMultipartUpload
+			xml.Unmarshal(body, &c)
+			for _, p := range c.Parts {
+				if p.ChecksumSHA256 != sums[p.PartNumber] {
+					t.Errorf("part %d: completed with checksum %q, want %q", p.PartNumber, p.ChecksumSHA256, sums[p.PartNumber])
+				}
+			}
+		}
+		m.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	u := UploadInput{
+		Bucket:    "bucket",
+		ObjectKey: "key",
+		Checksum:  ChecksumSHA256,
+		Body:      bytes.NewReader(bytes.Repeat([]byte("0123456789"), minPartSize/10+1)),
+	}
+	if _, err := s3.MultipartUpload(MultipartUploadInput{UploadInput: u}); err != nil {
+		t.Fatalf("S3.MultipartUpload() error = %v", err)
+	}
+	if len(sums) != 2 {
+		t.Errorf("S3.MultipartUpload() uploaded %d parts, want 2", len(sums))
+	}
+
+	if _, err := s3.FileUpload(u); err == nil {
+		t.Errorf("S3.FileUpload() error = nil, want an error for the unsupported Checksum")
+	}
+}
+
+func TestS3_FileDownload_VerifyChecksum(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("x-amz-checksum-mode"); got != "ENABLED" {
+			t.Errorf("x-amz-checksum-mode = %q, want ENABLED", got)
+		}
+		switch r.URL.Path {
+		case "/bucket/valid.txt":
+			w.Header().Set("x-amz-checksum-crc32c", "yZRlqg==")
+		case "/bucket/corrupt.txt":
+			w.Header().Set("x-amz-checksum-sha256", "uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek=")
+		case "/bucket/multipart.txt":
+			w.Header().Set("x-amz-checksum-crc32", "AAAAAA==-2")
+		}
+		if r.URL.Path == "/bucket/corrupt.txt" {
+			io.WriteString(w, "hello wOrld")
+			return
+		}
+		io.WriteString(w, "hello world")
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	tests := []struct {
+		key     string
+		wantErr error
+	}{
+		{"valid.txt", nil},
+		{"corrupt.txt", ErrChecksumMismatch},
+		{"multipart.txt", nil},
+		{"unknown.txt", nil},
+	}
+	for _, tt := range tests {
+		body, err := s3.FileDownload(DownloadInput{Bucket: "bucket", ObjectKey: tt.key, VerifyChecksum: true})
+		if err != nil {
+			t.Fatalf("S3.FileDownload(%s) error = %v", tt.key, err)
+		}
+		_, err = ioutil.ReadAll(body)
+		body.Close()
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("S3.FileDownload(%s) read error = %v, want %v", tt.key, err, tt.wantErr)
+		}
+	}
+}
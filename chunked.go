@@ -0,0 +1,219 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// streamingPayload is sent as the x-amz-content-sha256
+	// of a body encoded with aws-chunked.
+	streamingPayload = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	// chunkAlgorithm starts the string to sign of a chunk.
+	chunkAlgorithm = "AWS4-HMAC-SHA256-PAYLOAD"
+	// chunkSize is the size of the chunks of
+	// the body, except for the last one.
+	chunkSize = 64 * 1024
+	// maxPutSize is the largest object accepted
+	// by a single PUT request.
+	maxPutSize = 5 * 1024 * 1024 * 1024
+)
+
+// emptyHash is the hex-encoded SHA256 of an empty string.
+const emptyHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// chunkSigner signs the chunks of a body encoded with aws-chunked,
+// using the same key, date and scope as the request.
+// (https://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-streaming.html)
+type chunkSigner struct {
+	key   []byte
+	date  string
+	scope string
+}
+
+// signChunk returns the signature of a chunk, which depends
+// on the signature of the previous chunk, or of the request
+// for the first chunk.
+func (c chunkSigner) signChunk(chunkBody []byte, prevSignature string) string {
+	sum := sha256.Sum256(chunkBody)
+	stringToSign := chunkAlgorithm + "\n" +
+		c.date + "\n" +
+		c.scope + "\n" +
+		prevSignature + "\n" +
+		emptyHash + "\n" +
+		hex.EncodeToString(sum[:])
+	return hex.EncodeToString(makeHMac(c.key, []byte(stringToSign)))
+}
+
+// chunkedWriter writes the aws-chunked encoding of what is
+// written to it to w, in signed chunks of size bytes. Close
+// must be called to write the last chunk and the final
+// empty chunk which ends the body.
+type chunkedWriter struct {
+	w      io.Writer
+	signer chunkSigner
+	sig    string
+	size   int
+	buf    []byte
+}
+
+func newChunkedWriter(w io.Writer, signer chunkSigner, seedSignature string, size int) *chunkedWriter {
+	return &chunkedWriter{
+		w:      w,
+		signer: signer,
+		sig:    seedSignature,
+		size:   size,
+		buf:    make([]byte, 0, size),
+	}
+}
+
+func (cw *chunkedWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := cw.size - len(cw.buf)
+		if n > len(p) {
+			n = len(p)
+		}
+		cw.buf = append(cw.buf, p[:n]...)
+		p = p[n:]
+		written += n
+
+		if len(cw.buf) == cw.size {
+			if err := cw.writeChunk(cw.buf); err != nil {
+				return written, err
+			}
+			cw.buf = cw.buf[:0]
+		}
+	}
+	return written, nil
+}
+
+// Close writes the buffered chunk, if any, and the final chunk.
+// It does not close the underlying writer.
+func (cw *chunkedWriter) Close() error {
+	if len(cw.buf) > 0 {
+		if err := cw.writeChunk(cw.buf); err != nil {
+			return err
+		}
+		cw.buf = cw.buf[:0]
+	}
+	return cw.writeChunk(nil)
+}
+
+func (cw *chunkedWriter) writeChunk(chunk []byte) error {
+	cw.sig = cw.signer.signChunk(chunk, cw.sig)
+	if _, err := fmt.Fprintf(cw.w, "%x;chunk-signature=%s\r\n", len(chunk), cw.sig); err != nil {
+		return err
+	}
+	if _, err := cw.w.Write(chunk); err != nil {
+		return err
+	}
+	_, err := io.WriteString(cw.w, "\r\n")
+	return err
+}
+
+// chunkedLength returns the length of the aws-chunked encoding
+// of a body of size bytes in chunks of chunkSize bytes.
+func chunkedLength(size, chunkSize int64) int64 {
+	// <hex size>;chunk-signature=<64 hex digits>\r\n<data>\r\n
+	chunkLength := func(n int64) int64 {
+		return int64(len(strconv.FormatInt(n, 16))) + int64(len(";chunk-signature=")) + 64 + 2 + n + 2
+	}
+
+	length := (size / chunkSize) * chunkLength(chunkSize)
+	if rest := size % chunkSize; rest > 0 {
+		length += chunkLength(rest)
+	}
+	return length + chunkLength(0)
+}
+
+// putChunked makes a PUT call streaming size bytes of body encoded
+// with aws-chunked, so that every chunk is signed without the body
+// being read beforehand to compute its hash or size.
+func (s3 *S3) putChunked(u UploadInput, body io.Reader, size int64) (UploadResponse, error) {
+	pr, pw := io.Pipe()
+	req, err := s3.newRequest(context.Background(), http.MethodPut, u.Bucket, u.ObjectKey, "", pr)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+	req.ContentLength = chunkedLength(size, chunkSize)
+
+	u.setHeaders(req.Header)
+	req.Header.Set("Content-Encoding", "aws-chunked")
+	req.Header.Set("x-amz-decoded-content-length", strconv.FormatInt(size, 10))
+	req.Header.Set("x-amz-content-sha256", streamingPayload)
+
+	if err := s3.signRequest(req); err != nil {
+		return UploadResponse{}, err
+	}
+	signer, seed, err := s3.chunkSigner(req)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+
+	go func() {
+		cw := newChunkedWriter(pw, signer, seed, chunkSize)
+		n, err := io.Copy(cw, io.LimitReader(body, size))
+		if err == nil && n < size {
+			err = io.ErrUnexpectedEOF
+		}
+		if err == nil {
+			err = cw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	res, err := s3.do(req)
+	// Stop the writer if the request ended before reading the body.
+	pr.Close()
+	if err != nil {
+		return UploadResponse{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return UploadResponse{}, newResponseError(res)
+	}
+
+	return UploadResponse{
+		Location: s3.getURL(u.Bucket, u.ObjectKey),
+		Bucket:   u.Bucket,
+		Key:      u.ObjectKey,
+		ETag:     res.Header.Get("ETag"),
+	}, nil
+}
+
+// chunkSigner returns the signer of the chunks of a request
+// signed by signRequest, along with the signature of the
+// request, which seeds the signature of the first chunk.
+func (s3 *S3) chunkSigner(req *http.Request) (chunkSigner, string, error) {
+	auth := req.Header.Get("Authorization")
+	i := strings.LastIndex(auth, "Signature=")
+	if i < 0 {
+		return chunkSigner{}, "", errors.New("request is not signed")
+	}
+	t, err := time.Parse(amzDateISO8601TimeFormat, req.Header.Get("Date"))
+	if err != nil {
+		return chunkSigner{}, "", err
+	}
+
+	s3.mu.RLock()
+	defer s3.mu.RUnlock()
+	region, service := s3.requestScope(req)
+	return chunkSigner{
+		key:   s3.signKeys(t, region, service),
+		date:  t.Format(amzDateISO8601TimeFormat),
+		scope: s3.creds(t, region, service),
+	}, auth[i+len("Signature="):], nil
+}
@@ -0,0 +1,70 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+)
+
+const (
+	// localStackEndpoint is the default edge endpoint of LocalStack.
+	localStackEndpoint = "http://localhost:4566"
+	// localStackCredentials are accepted as both
+	// the access key and the secret key.
+	localStackCredentials = "test"
+	// minIORegion is the region of a MinIO server
+	// unless configured otherwise.
+	minIORegion = "us-east-1"
+)
+
+// NewLocalStack returns an instance of S3 for a LocalStack emulator
+// running on localhost, using path-style URLs and its default test
+// credentials. TLS certificates are not verified, as LocalStack serves
+// a self-signed one over HTTPS. opts are applied last to override
+// any of these, eg. WithEndpoint for a remote LocalStack.
+func NewLocalStack(region string, opts ...Option) *S3 {
+	return NewWithOptions(append([]Option{
+		WithRegion(region),
+		WithAccessKey(localStackCredentials),
+		WithSecretKey(localStackCredentials),
+		WithEndpoint(localStackEndpoint),
+		WithClient(insecureClient()),
+		func(s3 *S3) {
+			s3.URLStyle = PathStyle
+		},
+	}, opts...)...)
+}
+
+// NewMinIO returns an instance of S3 for a MinIO server at endpoint,
+// its host and port, eg. localhost:9000, using path-style URLs and the
+// default region of MinIO. The server is reached over HTTPS if useSSL
+// is true and over plain HTTP otherwise. A scheme included in endpoint
+// is replaced accordingly. For a server using a self-signed certificate,
+// pass a client trusting it to SetClient.
+func NewMinIO(endpoint, accessKey, secretKey string, useSSL bool) *S3 {
+	scheme := "http://"
+	if useSSL {
+		scheme = "https://"
+	}
+	endpoint = strings.TrimPrefix(strings.TrimPrefix(endpoint, "http://"), "https://")
+
+	s3 := New(minIORegion, accessKey, secretKey)
+	s3.SetEndpoint(scheme + endpoint)
+	s3.URLStyle = PathStyle
+	return s3
+}
+
+// insecureClient returns an http client which does not verify TLS
+// certificates. Its transport is a copy of http.DefaultTransport,
+// or a new one if it was replaced by another http.RoundTripper.
+func insecureClient() *http.Client {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = t.Clone()
+	}
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,59 @@
+package gos3
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewLocalStack(t *testing.T) {
+	s3 := NewLocalStack("eu-west-1")
+	if got := s3.getURL("bucket", "key"); got != "http://localhost:4566/bucket/key" {
+		t.Errorf("NewLocalStack() URL = %s", got)
+	}
+	if s3.Region != "eu-west-1" || s3.AccessKey != "test" || s3.SecretKey != "test" {
+		t.Errorf("NewLocalStack() got = %+v", s3)
+	}
+	transport, ok := s3.getClient().Transport.(*http.Transport)
+	if !ok || !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("NewLocalStack() client verifies TLS certificates")
+	}
+
+	s3 = NewLocalStack("eu-west-1", WithEndpoint("https://localstack:4566"), WithAccessKey("ak"))
+	if got := s3.getURL("bucket", "key"); got != "https://localstack:4566/bucket/key" || s3.AccessKey != "ak" {
+		t.Errorf("NewLocalStack() with options got URL %s and access key %s", got, s3.AccessKey)
+	}
+}
+
+func TestNewMinIO(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		useSSL   bool
+		want     string
+	}{
+		{"localhost:9000", false, "http://localhost:9000/bucket/key"},
+		{"minio.example.com", true, "https://minio.example.com/bucket/key"},
+		{"http://minio.example.com", true, "https://minio.example.com/bucket/key"},
+		{"https://localhost:9000", false, "http://localhost:9000/bucket/key"},
+	}
+	for _, tt := range tests {
+		s3 := NewMinIO(tt.endpoint, "minioadmin", "minioadmin", tt.useSSL)
+		if got := s3.getURL("bucket", "key"); got != tt.want {
+			t.Errorf("NewMinIO(%s, %v) URL = %s, want %s", tt.endpoint, tt.useSSL, got, tt.want)
+		}
+		if s3.Region != "us-east-1" || s3.AccessKey != "minioadmin" || s3.SecretKey != "minioadmin" {
+			t.Errorf("NewMinIO(%s, %v) got = %+v", tt.endpoint, tt.useSSL, s3)
+		}
+	}
+}
+
+func TestInsecureClient_CustomDefaultTransport(t *testing.T) {
+	defer func(rt http.RoundTripper) { http.DefaultTransport = rt }(http.DefaultTransport)
+	http.DefaultTransport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, nil
+	})
+
+	transport, ok := insecureClient().Transport.(*http.Transport)
+	if !ok || !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("insecureClient() transport = %#v", insecureClient().Transport)
+	}
+}
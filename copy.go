@@ -0,0 +1,396 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Values of CopyInput.MetadataDirective.
+const (
+	// MetadataDirectiveCopy keeps the metadata of the source object.
+	MetadataDirectiveCopy = "COPY"
+	// MetadataDirectiveReplace replaces the metadata of
+	// the source object with the one in the CopyInput.
+	MetadataDirectiveReplace = "REPLACE"
+)
+
+// CopyInput is passed to FileCopy as a parameter.
+type CopyInput struct {
+	// essential fields
+	SourceBucket string
+	SourceKey    string
+	DestBucket   string
+	DestKey      string
+
+	// optional fields
+	MetadataDirective string
+
+	// The metadata below is only sent when MetadataDirective
+	// is REPLACE, fields left empty are removed from the copy.
+	Metadata           map[string]string
+	ContentType        string
+	CacheControl       string
+	ContentDisposition string
+
+	// CopyTags makes FileCopy copy the tags of the source object
+	// to the copy, replacing any tags it was given by S3. This takes
+	// two extra API calls once the object is copied, GetObjectTagging
+	// on the source and PutObjectTagging on the copy.
+	CopyTags bool
+}
+
+// CopyOutput is returned by FileCopy.
+type CopyOutput struct {
+	ETag         string
+	LastModified time.Time
+}
+
+// copyObjectResult receives the CopyObjectResult XML.
+// S3 may also send a 200 OK with an <Error> document,
+// which is detected through XMLName.
+type copyObjectResult struct {
+	XMLName      xml.Name
+	ETag         string    `xml:"ETag"`
+	LastModified time.Time `xml:"LastModified"`
+}
+
+// FileCopy makes a PUT call to copy an object within S3,
+// without downloading and uploading it again.
+// Objects larger than 5 GB cannot be copied this way.
+func (s3 *S3) FileCopy(u CopyInput) (CopyOutput, error) {
+	switch u.MetadataDirective {
+	case "", MetadataDirectiveCopy, MetadataDirectiveReplace:
+	default:
+		return CopyOutput{}, fmt.Errorf("invalid metadata directive %q", u.MetadataDirective)
+	}
+
+	req, err := s3.newRequest(
+		context.Background(), http.MethodPut, u.DestBucket, u.DestKey, "", nil,
+	)
+	if err != nil {
+		return CopyOutput{}, err
+	}
+
+	req.Header.Set("x-amz-copy-source", copySource(u.SourceBucket, u.SourceKey))
+	if u.MetadataDirective != "" {
+		req.Header.Set("x-amz-metadata-directive", u.MetadataDirective)
+	}
+	if u.MetadataDirective == MetadataDirectiveReplace {
+		for k, v := range u.Metadata {
+			req.Header.Set(amzMetaPrefix+strings.ToLower(k), v)
+		}
+		if u.ContentType != "" {
+			req.Header.Set("Content-Type", u.ContentType)
+		}
+		if u.CacheControl != "" {
+			req.Header.Set("Cache-Control", u.CacheControl)
+		}
+		if u.ContentDisposition != "" {
+			req.Header.Set("Content-Disposition", u.ContentDisposition)
+		}
+	}
+
+	if err := s3.signRequest(req); err != nil {
+		return CopyOutput{}, err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return CopyOutput{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return CopyOutput{}, newResponseError(res)
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return CopyOutput{}, err
+	}
+
+	var result copyObjectResult
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return CopyOutput{}, err
+	}
+	if result.XMLName.Local == "Error" {
+		return CopyOutput{}, parseS3Error(res.StatusCode, res.Header, data)
+	}
+
+	if u.CopyTags {
+		tags, err := s3.GetObjectTagging(u.SourceBucket, u.SourceKey)
+		if err != nil {
+			return CopyOutput{}, fmt.Errorf("object copied, getting the tags of the source: %w", err)
+		}
+		if err := s3.PutObjectTagging(u.DestBucket, u.DestKey, tags); err != nil {
+			return CopyOutput{}, fmt.Errorf("object copied, putting the tags of the copy: %w", err)
+		}
+	}
+
+	return CopyOutput{
+		ETag:         result.ETag,
+		LastModified: result.LastModified,
+	}, nil
+}
+
+// FileCopyWithMetadata is like FileCopy but replaces the metadata
+// of the source object with the one in u. When the source and the
+// destination are the same object, it updates the metadata in place
+// without uploading the object again.
+func (s3 *S3) FileCopyWithMetadata(u CopyInput) (CopyOutput, error) {
+	u.MetadataDirective = MetadataDirectiveReplace
+	return s3.FileCopy(u)
+}
+
+// FileMoveInput is passed to FileMove as a parameter.
+type FileMoveInput struct {
+	SourceBucket string
+	SourceKey    string
+	DestBucket   string
+	DestKey      string
+}
+
+// MoveError is returned by FileMove when the object was copied
+// to its destination but its source could not be deleted, so
+// that callers can delete either of them.
+type MoveError struct {
+	// DestBucket and DestKey identify the copy.
+	DestBucket string
+	DestKey    string
+	// Err is the error returned by FileDelete.
+	Err error
+}
+
+func (e *MoveError) Error() string {
+	return fmt.Sprintf("copied to %s/%s but failed to delete the source: %v", e.DestBucket, e.DestKey, e.Err)
+}
+
+// Unwrap returns the error returned by FileDelete.
+func (e *MoveError) Unwrap() error {
+	return e.Err
+}
+
+// FileMove moves an object by copying it with FileCopy and deleting
+// the source once the copy succeeded, as S3 has no rename. If the delete
+// fails, the returned error is a *MoveError. Objects larger than 5 GB
+// cannot be moved this way.
+func (s3 *S3) FileMove(in FileMoveInput) error {
+	_, err := s3.FileCopy(CopyInput{
+		SourceBucket: in.SourceBucket,
+		SourceKey:    in.SourceKey,
+		DestBucket:   in.DestBucket,
+		DestKey:      in.DestKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s3.FileDelete(DeleteInput{Bucket: in.SourceBucket, ObjectKey: in.SourceKey}); err != nil {
+		return &MoveError{DestBucket: in.DestBucket, DestKey: in.DestKey, Err: err}
+	}
+	return nil
+}
+
+// MultipartCopyInput is passed to MultipartCopy as a parameter.
+type MultipartCopyInput struct {
+	SourceBucket string
+	SourceKey    string
+	DestBucket   string
+	DestKey      string
+
+	// PartSize is the size of every part except the last one.
+	// Defaults to 5 MB, which is also the minimum allowed by S3,
+	// or to the size needed to fit objects larger than 48.8 GB
+	// in the maximum of 10000 parts.
+	PartSize int64
+
+	// Concurrency is the number of parts copied at once,
+	// defaults to 5. No data goes through the client.
+	Concurrency int
+}
+
+// MultipartCopy copies an object within S3 using the multipart upload
+// API, copying ranges of u.PartSize bytes of the source as parts, up to
+// u.Concurrency at once. Unlike FileCopy, it can copy objects larger
+// than 5 GB. The content type and metadata of the source are kept.
+// If any part fails, no further part is started and the upload
+// is aborted.
+func (s3 *S3) MultipartCopy(u MultipartCopyInput) (UploadResponse, error) {
+	if u.PartSize != 0 && u.PartSize < minPartSize {
+		return UploadResponse{}, fmt.Errorf("part size %d is smaller than the minimum of %d bytes", u.PartSize, minPartSize)
+	}
+	concurrency := u.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	head, err := s3.FileHead(DownloadInput{Bucket: u.SourceBucket, ObjectKey: u.SourceKey})
+	if err != nil {
+		return UploadResponse{}, err
+	}
+	size := head.ContentLength
+	partSize := u.PartSize
+	if partSize == 0 {
+		partSize = copyPartSize(size)
+	}
+	numParts := (size + partSize - 1) / partSize
+	if numParts == 0 {
+		// S3 needs at least one part, even if it is empty.
+		numParts = 1
+	}
+	if numParts > maxParts {
+		return UploadResponse{}, fmt.Errorf("object of %d bytes needs %d parts, more than the maximum of %d", size, numParts, maxParts)
+	}
+
+	uploadID, err := s3.initiateMultipartUpload(UploadInput{
+		Bucket:      u.DestBucket,
+		ObjectKey:   u.DestKey,
+		ContentType: head.ContentType,
+		Metadata:    head.Metadata,
+	})
+	if err != nil {
+		return UploadResponse{}, err
+	}
+
+	var (
+		source = copySource(u.SourceBucket, u.SourceKey)
+		parts  = make([]CompletedPart, numParts)
+		sem    = make(chan struct{}, concurrency)
+		wg     sync.WaitGroup
+
+		mu       sync.Mutex
+		firstErr error
+	)
+	for i := range parts {
+		// An empty object is copied as a single part without a range.
+		var byteRange string
+		if size > 0 {
+			start := int64(i) * partSize
+			end := start + partSize
+			if end > size {
+				end = size
+			}
+			byteRange = fmt.Sprintf("bytes=%d-%d", start, end-1)
+		}
+
+		// The check follows the wait for a free slot, so that
+		// the failure of the part which freed it is seen.
+		sem <- struct{}{}
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, byteRange string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			etag, err := s3.uploadPartCopy(u.DestBucket, u.DestKey, uploadID, i+1, source, byteRange)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+			parts[i] = CompletedPart{PartNumber: i + 1, ETag: etag}
+		}(i, byteRange)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		s3.AbortMultipartUpload(u.DestBucket, u.DestKey, uploadID)
+		return UploadResponse{}, firstErr
+	}
+
+	resp, err := s3.completeMultipartUpload(u.DestBucket, u.DestKey, uploadID, parts)
+	if err != nil {
+		s3.AbortMultipartUpload(u.DestBucket, u.DestKey, uploadID)
+		return UploadResponse{}, err
+	}
+	return resp, nil
+}
+
+// copyPartSize returns the part size used to copy an object of size
+// bytes, the minimum allowed by S3 unless the object would then
+// need more than the maximum number of parts.
+func copyPartSize(size int64) int64 {
+	partSize := (size + maxParts - 1) / maxParts
+	if partSize < minPartSize {
+		partSize = minPartSize
+	}
+	return partSize
+}
+
+// uploadPartCopy makes a PUT call to copy byteRange of source, or the
+// whole object if empty, as a part and returns the ETag of the part.
+func (s3 *S3) uploadPartCopy(bucket, key, uploadID string, partNumber int, source, byteRange string) (string, error) {
+	req, err := s3.newRequest(
+		context.Background(), http.MethodPut, bucket, key,
+		"?partNumber="+strconv.Itoa(partNumber)+"&uploadId="+url.QueryEscape(uploadID),
+		nil,
+	)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("x-amz-copy-source", source)
+	if byteRange != "" {
+		req.Header.Set("x-amz-copy-source-range", byteRange)
+	}
+
+	if err := s3.signRequest(req); err != nil {
+		return "", err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return "", fmt.Errorf("part %d: %w", partNumber, newResponseError(res))
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	// The CopyPartResult XML has the same fields as CopyObjectResult.
+	var result copyObjectResult
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return "", err
+	}
+	if result.XMLName.Local == "Error" {
+		return "", fmt.Errorf("part %d: %w", partNumber, parseS3Error(res.StatusCode, res.Header, data))
+	}
+	return result.ETag, nil
+}
+
+// copySource returns the value of the x-amz-copy-source
+// header, with each segment of the key URI-encoded as for signing,
+// since S3 would otherwise decode characters like "+" as a space.
+func copySource(bucket, key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = uriEncode(s)
+	}
+	return "/" + bucket + "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,305 @@
+package gos3
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestS3_FileCopy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.EscapedPath() != "/dest/copy.txt" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if got := r.Header.Get("x-amz-copy-source"); got != "/src/dir/hello%20world.txt" {
+			t.Errorf("x-amz-copy-source = %s", got)
+		}
+		if r.Header.Get("x-amz-metadata-directive") != "REPLACE" || r.Header.Get("x-amz-meta-owner") != "gopher" {
+			t.Errorf("unexpected metadata headers %v", r.Header)
+		}
+		io.WriteString(w, `<CopyObjectResult><LastModified>2013-05-24T00:00:00.000Z</LastModified><ETag>"etag"</ETag></CopyObjectResult>`)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	out, err := s3.FileCopy(CopyInput{
+		SourceBucket:      "src",
+		SourceKey:         "dir/hello world.txt",
+		DestBucket:        "dest",
+		DestKey:           "copy.txt",
+		MetadataDirective: MetadataDirectiveReplace,
+		Metadata:          map[string]string{"Owner": "gopher"},
+	})
+	if err != nil {
+		t.Fatalf("S3.FileCopy() error = %v", err)
+	}
+	if out.ETag != `"etag"` || out.LastModified.IsZero() {
+		t.Errorf("S3.FileCopy() got = %+v", out)
+	}
+
+	if _, err := s3.FileCopy(CopyInput{MetadataDirective: "MOVE"}); err == nil {
+		t.Errorf("S3.FileCopy() expected an error for an invalid metadata directive")
+	}
+}
+
+func TestS3_FileCopy_Escape(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("x-amz-copy-source"); got != "/src/dir/a%2Bb%20c%3D%26.txt" {
+			t.Errorf("x-amz-copy-source = %s", got)
+		}
+		io.WriteString(w, `<CopyObjectResult><LastModified>2013-05-24T00:00:00.000Z</LastModified><ETag>"etag"</ETag></CopyObjectResult>`)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	if _, err := s3.FileCopy(CopyInput{
+		SourceBucket: "src",
+		SourceKey:    "dir/a+b c=&.txt",
+		DestBucket:   "dest",
+		DestKey:      "copy.txt",
+	}); err != nil {
+		t.Fatalf("S3.FileCopy() error = %v", err)
+	}
+}
+
+func TestS3_FileCopyWithMetadata(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-amz-metadata-directive") != "REPLACE" {
+			t.Errorf("x-amz-metadata-directive = %s", r.Header.Get("x-amz-metadata-directive"))
+		}
+		if r.Header.Get("Content-Type") != "text/html" || r.Header.Get("Cache-Control") != "max-age=60" {
+			t.Errorf("unexpected metadata headers %v", r.Header)
+		}
+		io.WriteString(w, `<CopyObjectResult><ETag>"etag"</ETag></CopyObjectResult>`)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	_, err := s3.FileCopyWithMetadata(CopyInput{
+		SourceBucket: "bucket",
+		SourceKey:    "index.html",
+		DestBucket:   "bucket",
+		DestKey:      "index.html",
+		ContentType:  "text/html",
+		CacheControl: "max-age=60",
+	})
+	if err != nil {
+		t.Fatalf("S3.FileCopyWithMetadata() error = %v", err)
+	}
+}
+
+func TestS3_MultipartCopy(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		ranges  = map[string]string{}
+		parts   []CompletedPart
+		aborted bool
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		q := r.URL.Query()
+		_, uploads := q["uploads"]
+		switch {
+		case r.Method == http.MethodHead && r.URL.Path == "/src/big.bin":
+			w.Header().Set("Content-Length", fmt.Sprint(2*minPartSize+1))
+			w.Header().Set("Content-Type", "application/x-tar")
+			w.Header().Set("x-amz-meta-owner", "gopher")
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost && uploads:
+			if r.Header.Get("Content-Type") != "application/x-tar" || r.Header.Get("x-amz-meta-owner") != "gopher" {
+				t.Errorf("source metadata not kept: %v", r.Header)
+			}
+			io.WriteString(w, `<InitiateMultipartUploadResult><UploadId>upload-id</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == http.MethodPut:
+			if got := r.Header.Get("x-amz-copy-source"); got != "/src/big.bin" {
+				t.Errorf("x-amz-copy-source = %s", got)
+			}
+			if r.URL.Path == "/dest/fail.bin" && q.Get("partNumber") == "2" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			ranges[q.Get("partNumber")] = r.Header.Get("x-amz-copy-source-range")
+			fmt.Fprintf(w, `<CopyPartResult><ETag>"etag-%s"</ETag></CopyPartResult>`, q.Get("partNumber"))
+		case r.Method == http.MethodPost:
+			var c completeMultipartUpload
+			data, _ := ioutil.ReadAll(r.Body)
+			xml.Unmarshal(data, &c)
+			parts = c.Parts
+			io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>dest</Bucket><Key>big.bin</Key><ETag>"final"</ETag></CompleteMultipartUploadResult>`)
+		case r.Method == http.MethodDelete:
+			aborted = true
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	input := MultipartCopyInput{
+		SourceBucket: "src",
+		SourceKey:    "big.bin",
+		DestBucket:   "dest",
+		DestKey:      "big.bin",
+		Concurrency:  2,
+	}
+	resp, err := s3.MultipartCopy(input)
+	if err != nil {
+		t.Fatalf("S3.MultipartCopy() error = %v", err)
+	}
+	if resp.ETag != `"final"` {
+		t.Errorf("S3.MultipartCopy() got = %+v", resp)
+	}
+	wantRanges := map[string]string{
+		"1": fmt.Sprintf("bytes=0-%d", minPartSize-1),
+		"2": fmt.Sprintf("bytes=%d-%d", minPartSize, 2*minPartSize-1),
+		"3": fmt.Sprintf("bytes=%d-%d", 2*minPartSize, 2*minPartSize),
+	}
+	if !reflect.DeepEqual(ranges, wantRanges) {
+		t.Errorf("S3.MultipartCopy() copied ranges %v, want %v", ranges, wantRanges)
+	}
+	wantParts := []CompletedPart{
+		{PartNumber: 1, ETag: `"etag-1"`},
+		{PartNumber: 2, ETag: `"etag-2"`},
+		{PartNumber: 3, ETag: `"etag-3"`},
+	}
+	if !reflect.DeepEqual(parts, wantParts) {
+		t.Errorf("S3.MultipartCopy() completed parts %v, want %v", parts, wantParts)
+	}
+
+	// No part is started once one failed.
+	ranges = map[string]string{}
+	input.DestKey = "fail.bin"
+	input.Concurrency = 1
+	if _, err := s3.MultipartCopy(input); err == nil || !aborted {
+		t.Errorf("S3.MultipartCopy() error = %v, aborted = %v for a failing part", err, aborted)
+	}
+	if _, ok := ranges["3"]; ok || len(ranges) != 1 {
+		t.Errorf("S3.MultipartCopy() copied ranges %v after a failing part", ranges)
+	}
+
+	input.SourceKey = "missing.bin"
+	if _, err := s3.MultipartCopy(input); err == nil {
+		t.Errorf("S3.MultipartCopy() expected an error for a missing source")
+	}
+}
+
+func TestCopyPartSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want int64
+	}{
+		{0, minPartSize},
+		{maxParts * minPartSize, minPartSize},
+		{maxParts*minPartSize + 1, minPartSize + 1},
+		{5 << 40, (5<<40 + maxParts - 1) / maxParts},
+	}
+	for _, tt := range tests {
+		got := copyPartSize(tt.size)
+		if got != tt.want {
+			t.Errorf("copyPartSize(%d) = %d, want %d", tt.size, got, tt.want)
+		}
+		if (tt.size+got-1)/got > maxParts {
+			t.Errorf("copyPartSize(%d) = %d needs more than %d parts", tt.size, got, maxParts)
+		}
+	}
+}
+
+func TestS3_FileMove(t *testing.T) {
+	var deleted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			if r.Header.Get("x-amz-copy-source") == "/src/missing.txt" {
+				w.WriteHeader(http.StatusNotFound)
+				io.WriteString(w, `<Error><Code>NoSuchKey</Code></Error>`)
+				return
+			}
+			io.WriteString(w, `<CopyObjectResult><ETag>"etag"</ETag></CopyObjectResult>`)
+		case http.MethodDelete:
+			if r.URL.Path == "/src/locked.txt" {
+				w.WriteHeader(http.StatusForbidden)
+				io.WriteString(w, `<Error><Code>AccessDenied</Code></Error>`)
+				return
+			}
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	if err := s3.FileMove(FileMoveInput{SourceBucket: "src", SourceKey: "a.txt", DestBucket: "dest", DestKey: "b.txt"}); err != nil {
+		t.Fatalf("S3.FileMove() error = %v", err)
+	}
+	if want := []string{"/src/a.txt"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("S3.FileMove() deleted %v, want %v", deleted, want)
+	}
+
+	err := s3.FileMove(FileMoveInput{SourceBucket: "src", SourceKey: "missing.txt", DestBucket: "dest", DestKey: "b.txt"})
+	if !errors.Is(err, ErrNoSuchKey) || len(deleted) != 1 {
+		t.Errorf("S3.FileMove() error = %v after deleting %v, want ErrNoSuchKey", err, deleted)
+	}
+
+	err = s3.FileMove(FileMoveInput{SourceBucket: "src", SourceKey: "locked.txt", DestBucket: "dest", DestKey: "b.txt"})
+	var moveErr *MoveError
+	if !errors.As(err, &moveErr) || moveErr.DestKey != "b.txt" || !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("S3.FileMove() error = %v, want a MoveError matching ErrAccessDenied", err)
+	}
+}
+
+func TestS3_FileCopy_CopyTags(t *testing.T) {
+	var calls []string
+	var putTags []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, tagging := r.URL.Query()["tagging"]
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodPut && !tagging:
+			io.WriteString(w, `<CopyObjectResult><ETag>"etag"</ETag></CopyObjectResult>`)
+		case r.Method == http.MethodGet && tagging:
+			io.WriteString(w, `<Tagging><TagSet><Tag><Key>team</Key><Value>a</Value></Tag></TagSet></Tagging>`)
+		case r.Method == http.MethodPut && tagging:
+			putTags, _ = ioutil.ReadAll(r.Body)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	_, err := s3.FileCopy(CopyInput{
+		SourceBucket: "src",
+		SourceKey:    "a.txt",
+		DestBucket:   "dest",
+		DestKey:      "b.txt",
+		CopyTags:     true,
+	})
+	if err != nil {
+		t.Fatalf("S3.FileCopy() error = %v", err)
+	}
+	if want := []string{"PUT /dest/b.txt", "GET /src/a.txt", "PUT /dest/b.txt"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("S3.FileCopy() made calls %v, want %v", calls, want)
+	}
+	if want := `<Tagging><TagSet><Tag><Key>team</Key><Value>a</Value></Tag></TagSet></Tagging>`; string(putTags) != want {
+		t.Errorf("S3.FileCopy() put tags %s, want %s", putTags, want)
+	}
+}
@@ -0,0 +1,49 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// CORSRule allows browsers on AllowedOrigins to make
+// cross-origin requests to the objects of a bucket.
+type CORSRule struct {
+	AllowedOrigins []string `xml:"AllowedOrigin"`
+	AllowedMethods []string `xml:"AllowedMethod"`
+	AllowedHeaders []string `xml:"AllowedHeader"`
+	// MaxAgeSeconds is how long browsers may cache
+	// the response to a preflight request.
+	MaxAgeSeconds int `xml:"MaxAgeSeconds,omitempty"`
+}
+
+// corsConfiguration is the XML representation of
+// the CORS rules of a bucket.
+type corsConfiguration struct {
+	XMLName xml.Name   `xml:"CORSConfiguration"`
+	Rules   []CORSRule `xml:"CORSRule"`
+}
+
+// PutBucketCORS makes a PUT call to replace
+// the CORS rules of a bucket with rules.
+func (s3 *S3) PutBucketCORS(bucket string, rules []CORSRule) error {
+	return s3.doXML(http.MethodPut, bucket, "", "?cors", nil, corsConfiguration{Rules: rules}, nil)
+}
+
+// GetBucketCORS makes a GET call and returns the CORS rules of
+// a bucket. If the bucket has none, the returned error
+// matches ErrNotFound.
+func (s3 *S3) GetBucketCORS(bucket string) ([]CORSRule, error) {
+	var config corsConfiguration
+	if err := s3.doXML(http.MethodGet, bucket, "", "?cors", nil, nil, &config); err != nil {
+		return nil, err
+	}
+	return config.Rules, nil
+}
+
+// DeleteBucketCORS makes a DELETE call to remove all the CORS rules of a bucket.
+func (s3 *S3) DeleteBucketCORS(bucket string) error {
+	return s3.doXML(http.MethodDelete, bucket, "", "?cors", nil, nil, nil)
+}
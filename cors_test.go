@@ -0,0 +1,57 @@
+package gos3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestS3_BucketCORS(t *testing.T) {
+	var stored []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["cors"]; !ok || r.URL.Path != "/bucket" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			stored, _ = ioutil.ReadAll(r.Body)
+		case http.MethodGet:
+			w.Write(stored)
+		case http.MethodDelete:
+			stored = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	rules := []CORSRule{{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{"GET", "PUT"},
+		AllowedHeaders: []string{"*"},
+		MaxAgeSeconds:  3000,
+	}}
+	if err := s3.PutBucketCORS("bucket", rules); err != nil {
+		t.Fatalf("S3.PutBucketCORS() error = %v", err)
+	}
+	if !strings.Contains(string(stored), "<AllowedMethod>GET</AllowedMethod><AllowedMethod>PUT</AllowedMethod>") {
+		t.Errorf("S3.PutBucketCORS() sent = %s", stored)
+	}
+
+	got, err := s3.GetBucketCORS("bucket")
+	if err != nil {
+		t.Fatalf("S3.GetBucketCORS() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, rules) {
+		t.Errorf("S3.GetBucketCORS() = %+v, want %+v", got, rules)
+	}
+
+	if err := s3.DeleteBucketCORS("bucket"); err != nil {
+		t.Fatalf("S3.DeleteBucketCORS() error = %v", err)
+	}
+}
@@ -0,0 +1,324 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// credentialRetryInterval is how long a credential refresher
+	// waits before trying again after a failed refresh.
+	credentialRetryInterval = time.Minute
+
+	// ecsCredentialsHost serves the credentials of the task role
+	// at the path in AWS_CONTAINER_CREDENTIALS_RELATIVE_URI.
+	ecsCredentialsHost = "http://169.254.170.2"
+)
+
+// metadataClient fetches credentials from the instance and container
+// metadata endpoints. Its timeout keeps a credential chain from
+// hanging when the process does not run on EC2 or ECS.
+var metadataClient = &http.Client{Timeout: 5 * time.Second}
+
+// credentials are temporary credentials
+// which are valid until Expiration.
+type credentials struct {
+	AccessKey  string
+	SecretKey  string
+	Token      string
+	Expiration time.Time
+}
+
+// NewFromEnv returns an instance of S3 configured from the standard
+// AWS environment variables: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+// AWS_SESSION_TOKEN, AWS_REGION (or AWS_DEFAULT_REGION) and
+// AWS_ENDPOINT_URL. The access key and secret key are mandatory.
+func NewFromEnv() (*S3, error) {
+	accessKey, secretKey, token, err := EnvCredentialProvider{}.Retrieve()
+	if err != nil {
+		return nil, err
+	}
+
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+
+	s3 := New(region, accessKey, secretKey)
+	s3.SetToken(token)
+	s3.SetEndpoint(os.Getenv("AWS_ENDPOINT_URL"))
+	return s3, nil
+}
+
+// NewFromProfile returns an instance of S3 using the credentials of
+// profile from the shared credentials file, ~/.aws/credentials or the
+// path in AWS_SHARED_CREDENTIALS_FILE. If profile is empty, AWS_PROFILE
+// or else "default" is used. If region is empty, it is read from the
+// profile in the shared config file, ~/.aws/config or the path in
+// AWS_CONFIG_FILE.
+func NewFromProfile(profile, region string) (*S3, error) {
+	profile = profileName(profile)
+	section, err := loadProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if region == "" {
+		configFile, err := sharedFilePath("AWS_CONFIG_FILE", "config")
+		if err != nil {
+			return nil, err
+		}
+		sections, err := parseINIFile(configFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading shared config file: %w", err)
+		}
+		// Profiles other than the default one are
+		// prefixed with "profile" in the config file.
+		name := profile
+		if name != "default" {
+			name = "profile " + name
+		}
+		region = sections[name]["region"]
+	}
+
+	s3 := New(region, section["aws_access_key_id"], section["aws_secret_access_key"])
+	s3.SetToken(section["aws_session_token"])
+	return s3, nil
+}
+
+// profileName returns profile, or else
+// AWS_PROFILE, or else "default".
+func profileName(profile string) string {
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	return profile
+}
+
+// loadProfile returns the section of profile in the shared
+// credentials file, which must contain the access key
+// and the secret key.
+func loadProfile(profile string) (map[string]string, error) {
+	credsFile, err := sharedFilePath("AWS_SHARED_CREDENTIALS_FILE", "credentials")
+	if err != nil {
+		return nil, err
+	}
+	sections, err := parseINIFile(credsFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading shared credentials file: %w", err)
+	}
+	section, ok := sections[profile]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in %s", profile, credsFile)
+	}
+	if section["aws_access_key_id"] == "" || section["aws_secret_access_key"] == "" {
+		return nil, fmt.Errorf("profile %q in %s has no aws_access_key_id or aws_secret_access_key", profile, credsFile)
+	}
+	return section, nil
+}
+
+// sharedFilePath returns the path in the environment variable env,
+// or the file name in the .aws directory of the home directory.
+func sharedFilePath(env, name string) (string, error) {
+	if path := os.Getenv(env); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".aws", name), nil
+}
+
+// parseINIFile parses the INI format used by the shared
+// credentials and config files into a map of sections.
+func parseINIFile(path string) (map[string]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		sections = map[string]map[string]string{}
+		section  map[string]string
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+		case line[0] == '[' && line[len(line)-1] == ']':
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if sections[name] == nil {
+				sections[name] = map[string]string{}
+			}
+			section = sections[name]
+		case section != nil:
+			if i := strings.IndexByte(line, '='); i > 0 {
+				section[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+			}
+		}
+	}
+	return sections, scanner.Err()
+}
+
+// NewUsingECSTaskRole returns an instance of S3 using the credentials
+// of the IAM role of the ECS task the process runs in. They are fetched
+// from the path in AWS_CONTAINER_CREDENTIALS_RELATIVE_URI, or else from
+// the URL in AWS_CONTAINER_CREDENTIALS_FULL_URI, in which case the
+// AWS_CONTAINER_AUTHORIZATION_TOKEN is sent along if set.
+func NewUsingECSTaskRole(region string) (*S3, error) {
+	return newUsingECSTaskRoleImpl(ecsCredentialsHost, region)
+}
+
+func newUsingECSTaskRoleImpl(host, region string) (*S3, error) {
+	jsonResp, err := fetchECSCredentials(host)
+	if err != nil {
+		return nil, err
+	}
+
+	s3 := New(region, jsonResp.AccessKeyID, jsonResp.SecretAccessKey)
+	s3.SetToken(jsonResp.Token)
+	return s3, nil
+}
+
+// fetchECSCredentials gets the credentials of the task role from the
+// container credentials endpoint. The response has the same fields
+// as the one of the instance metadata.
+func fetchECSCredentials(host string) (IAMResponse, error) {
+	var uri, token string
+	if path := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); path != "" {
+		uri = host + path
+	} else if full := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"); full != "" {
+		uri = full
+		token = os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	} else {
+		return IAMResponse{}, errors.New("neither AWS_CONTAINER_CREDENTIALS_RELATIVE_URI nor AWS_CONTAINER_CREDENTIALS_FULL_URI is set")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return IAMResponse{}, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	resp, err := metadataClient.Do(req)
+	if err != nil {
+		return IAMResponse{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return IAMResponse{}, errors.New(http.StatusText(resp.StatusCode))
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return IAMResponse{}, err
+	}
+
+	var jsonResp IAMResponse
+	if err := json.Unmarshal(data, &jsonResp); err != nil {
+		return IAMResponse{}, err
+	}
+	return jsonResp, nil
+}
+
+// NewUsingIAMAutoRefresh is like NewUsingIAM, but keeps the credentials
+// up to date for long-running processes. A background goroutine fetches
+// new credentials from the instance metadata refreshBefore the current
+// ones expire, or at most once every minute if refreshBefore exceeds
+// their lifetime. The goroutine runs for the lifetime of the process,
+// use NewUsingIAMAutoRefreshWithContext to stop it.
+func NewUsingIAMAutoRefresh(region string, refreshBefore time.Duration) (*S3, error) {
+	return NewUsingIAMAutoRefreshWithContext(context.Background(), region, refreshBefore)
+}
+
+// NewUsingIAMAutoRefreshWithContext is like NewUsingIAMAutoRefresh
+// but the background goroutine stops once ctx is done, after
+// which the credentials are no longer refreshed.
+func NewUsingIAMAutoRefreshWithContext(ctx context.Context, region string, refreshBefore time.Duration) (*S3, error) {
+	return newUsingIAMAutoRefreshImpl(ctx, securityCredentialsURL, region, refreshBefore)
+}
+
+func newUsingIAMAutoRefreshImpl(ctx context.Context, baseURL, region string, refreshBefore time.Duration) (*S3, error) {
+	fetch := func() (credentials, error) {
+		jsonResp, err := fetchIAMCredentials(baseURL)
+		if err != nil {
+			return credentials{}, err
+		}
+		expiration, err := time.Parse(time.RFC3339, jsonResp.Expiration)
+		if err != nil {
+			return credentials{}, err
+		}
+		return credentials{
+			AccessKey:  jsonResp.AccessKeyID,
+			SecretKey:  jsonResp.SecretAccessKey,
+			Token:      jsonResp.Token,
+			Expiration: expiration,
+		}, nil
+	}
+
+	creds, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	s3 := New(region, creds.AccessKey, creds.SecretKey)
+	s3.SetToken(creds.Token)
+	go s3.refreshCredentials(ctx, creds.Expiration, refreshBefore, fetch)
+	return s3, nil
+}
+
+// refreshCredentials replaces the credentials with the ones
+// returned by fetch, refreshBefore they expire. If fetch fails,
+// it is retried after credentialRetryInterval. It returns once
+// ctx is done.
+func (s3 *S3) refreshCredentials(ctx context.Context, expiration time.Time, refreshBefore time.Duration, fetch func() (credentials, error)) {
+	wait := time.Until(expiration.Add(-refreshBefore))
+	for {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		creds, err := fetch()
+		if err != nil {
+			wait = credentialRetryInterval
+			continue
+		}
+
+		s3.mu.Lock()
+		s3.AccessKey = creds.AccessKey
+		s3.SecretKey = creds.SecretKey
+		s3.Token = creds.Token
+		s3.mu.Unlock()
+
+		// Credentials valid for less than refreshBefore
+		// would otherwise be fetched again right away.
+		wait = time.Until(creds.Expiration.Add(-refreshBefore))
+		if wait < credentialRetryInterval {
+			wait = credentialRetryInterval
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package gos3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// setenv sets an environment variable and
+// returns a function restoring its previous value.
+func setenv(key, value string) func() {
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	return func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	env := map[string]string{
+		"AWS_ACCESS_KEY_ID":     "ak",
+		"AWS_SECRET_ACCESS_KEY": "sk",
+		"AWS_SESSION_TOKEN":     "token",
+		"AWS_REGION":            "",
+		"AWS_DEFAULT_REGION":    "eu-west-1",
+		"AWS_ENDPOINT_URL":      "http://localhost:9000",
+	}
+	for k, v := range env {
+		defer setenv(k, v)()
+	}
+
+	s3, err := NewFromEnv()
+	if err != nil {
+		t.Fatalf("NewFromEnv() error = %v", err)
+	}
+	if s3.AccessKey != "ak" || s3.SecretKey != "sk" || s3.Token != "token" ||
+		s3.Region != "eu-west-1" || s3.Endpoint != "http://localhost:9000" {
+		t.Errorf("NewFromEnv() got = %+v", s3)
+	}
+
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	if _, err := NewFromEnv(); err == nil {
+		t.Errorf("NewFromEnv() expected an error without AWS_SECRET_ACCESS_KEY")
+	}
+}
+
+func TestNewFromProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gos3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	credsFile := filepath.Join(dir, "credentials")
+	ioutil.WriteFile(credsFile, []byte(`
+[default]
+aws_access_key_id = default-ak
+aws_secret_access_key = default-sk
+
+# a comment
+[dev]
+aws_access_key_id=dev-ak
+aws_secret_access_key=dev-sk
+aws_session_token=dev-token
+`), 0600)
+	configFile := filepath.Join(dir, "config")
+	ioutil.WriteFile(configFile, []byte(`
+[default]
+region = us-east-1
+
+[profile dev]
+region = ap-south-1
+`), 0600)
+	defer setenv("AWS_SHARED_CREDENTIALS_FILE", credsFile)()
+	defer setenv("AWS_CONFIG_FILE", configFile)()
+	defer setenv("AWS_PROFILE", "")()
+
+	s3, err := NewFromProfile("dev", "")
+	if err != nil {
+		t.Fatalf("NewFromProfile() error = %v", err)
+	}
+	if s3.AccessKey != "dev-ak" || s3.SecretKey != "dev-sk" || s3.Token != "dev-token" || s3.Region != "ap-south-1" {
+		t.Errorf("NewFromProfile() got = %+v", s3)
+	}
+
+	s3, err = NewFromProfile("", "eu-west-1")
+	if err != nil {
+		t.Fatalf("NewFromProfile() error = %v", err)
+	}
+	if s3.AccessKey != "default-ak" || s3.Region != "eu-west-1" {
+		t.Errorf("NewFromProfile() got = %+v", s3)
+	}
+
+	if _, err := NewFromProfile("missing", ""); err == nil {
+		t.Errorf("NewFromProfile() expected an error for a missing profile")
+	}
+}
+
+func TestS3_NewUsingIAMAutoRefresh(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() == "/" {
+			io.WriteString(w, "role")
+			return
+		}
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+
+		// The first credentials expire right away,
+		// the following ones are valid for an hour.
+		expiration := time.Now().Add(time.Second)
+		if n > 1 {
+			expiration = time.Now().Add(time.Hour)
+		}
+		fmt.Fprintf(w, `{"AccessKeyId": "key-%d", "SecretAccessKey": "secret-%d", "Token": "token-%d", "Expiration": "%s"}`,
+			n, n, n, expiration.UTC().Format(time.RFC3339))
+	}))
+	defer ts.Close()
+
+	s3, err := newUsingIAMAutoRefreshImpl(context.Background(), ts.URL, "us-east-1", 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewUsingIAMAutoRefresh() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		s3.mu.RLock()
+		accessKey, secretKey, token := s3.AccessKey, s3.SecretKey, s3.Token
+		s3.mu.RUnlock()
+		if accessKey == "key-2" {
+			if secretKey != "secret-2" || token != "token-2" {
+				t.Errorf("NewUsingIAMAutoRefresh() got = %s, %s, %s", accessKey, secretKey, token)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("NewUsingIAMAutoRefresh() credentials were not refreshed")
+}
+
+func TestS3_NewUsingIAMAutoRefresh_ShortLived(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() == "/" {
+			io.WriteString(w, "role")
+			return
+		}
+		mu.Lock()
+		calls++
+		mu.Unlock()
+
+		// The credentials always expire sooner than refreshBefore.
+		fmt.Fprintf(w, `{"AccessKeyId": "key", "SecretAccessKey": "secret", "Token": "token", "Expiration": "%s"}`,
+			time.Now().Add(time.Minute).UTC().Format(time.RFC3339))
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := newUsingIAMAutoRefreshImpl(ctx, ts.URL, "us-east-1", time.Hour); err != nil {
+		t.Fatalf("NewUsingIAMAutoRefreshWithContext() error = %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	// The first credentials are refreshed right away,
+	// the following ones after credentialRetryInterval.
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 2 {
+		t.Errorf("NewUsingIAMAutoRefreshWithContext() fetched credentials %d times, want 2", calls)
+	}
+}
+
+func TestNewUsingECSTaskRole(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/credentials/task" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("full") == "1" && r.Header.Get("Authorization") != "secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"AccessKeyId":"ak","SecretAccessKey":"sk","Token":"token","Expiration":"2030-01-01T00:00:00Z"}`))
+	}))
+	defer ts.Close()
+
+	t.Run("relative", func(t *testing.T) {
+		defer setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "/v2/credentials/task")()
+
+		s3, err := newUsingECSTaskRoleImpl(ts.URL, "us-east-1")
+		if err != nil {
+			t.Fatalf("NewUsingECSTaskRole() error = %v", err)
+		}
+		if s3.AccessKey != "ak" || s3.SecretKey != "sk" || s3.Token != "token" || s3.Region != "us-east-1" {
+			t.Errorf("NewUsingECSTaskRole() = %+v", s3)
+		}
+	})
+
+	t.Run("full", func(t *testing.T) {
+		defer setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")()
+		defer setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", ts.URL+"/v2/credentials/task?full=1")()
+		defer setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN", "secret-token")()
+
+		s3, err := newUsingECSTaskRoleImpl("http://invalid", "us-east-1")
+		if err != nil {
+			t.Fatalf("NewUsingECSTaskRole() error = %v", err)
+		}
+		if s3.AccessKey != "ak" || s3.Token != "token" {
+			t.Errorf("NewUsingECSTaskRole() = %+v", s3)
+		}
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		defer setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")()
+		defer setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", "")()
+
+		if _, err := newUsingECSTaskRoleImpl(ts.URL, "us-east-1"); err == nil {
+			t.Errorf("NewUsingECSTaskRole() expected an error")
+		}
+	})
+}
@@ -0,0 +1,114 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DownloadPrefixInput is passed to DownloadPrefix as a parameter.
+type DownloadPrefixInput struct {
+	Bucket   string
+	Prefix   string
+	LocalDir string
+
+	// Concurrency is the number of objects downloaded at once,
+	// defaults to 5.
+	Concurrency int
+	// SkipExisting skips objects for which a local file
+	// of the same size already exists.
+	SkipExisting bool
+}
+
+// DownloadPrefix downloads every object whose key starts with
+// in.Prefix into in.LocalDir, creating subdirectories after the
+// "/" separated key hierarchy. Failing objects do not stop the
+// download, their errors are combined in the returned error.
+func (s3 *S3) DownloadPrefix(in DownloadPrefixInput) error {
+	objects, err := s3.ListAllObjects(ListInput{Bucket: in.Bucket, Prefix: in.Prefix})
+	if err != nil {
+		return err
+	}
+
+	concurrency := in.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	var (
+		errs []string
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+	)
+	keys := make(chan ObjectInfo)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range keys {
+				if err := s3.downloadObject(in, obj); err != nil {
+					mu.Lock()
+					errs = append(errs, obj.Key+": "+err.Error())
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, obj := range objects {
+		// Keys ending with a slash are folder
+		// placeholders created by the console.
+		if strings.HasSuffix(obj.Key, "/") {
+			continue
+		}
+		keys <- obj
+	}
+	close(keys)
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d downloads failed: %s", len(errs), strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// downloadObject downloads a single object for DownloadPrefix.
+func (s3 *S3) downloadObject(in DownloadPrefixInput, obj ObjectInfo) error {
+	file := filepath.Join(in.LocalDir, filepath.FromSlash(obj.Key))
+	rel, err := filepath.Rel(in.LocalDir, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("key escapes %s", in.LocalDir)
+	}
+
+	if in.SkipExisting {
+		if info, err := os.Stat(file); err == nil && info.Size() == obj.Size {
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	body, err := s3.FileDownload(DownloadInput{Bucket: in.Bucket, ObjectKey: obj.Key})
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		os.Remove(file)
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,122 @@
+package gos3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestS3_DownloadPrefix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gos3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "skip.txt"), []byte("local"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	objects := map[string]string{
+		"/bucket/a.txt":         "hello",
+		"/bucket/sub/b.txt":     "world",
+		"/bucket/skip.txt":      "other",
+		"/bucket/../escape.txt": "nope",
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("list-type") == "2" {
+			w.Write([]byte(`<ListBucketResult>
+<Contents><Key>a.txt</Key><Size>5</Size></Contents>
+<Contents><Key>sub/</Key><Size>0</Size></Contents>
+<Contents><Key>sub/b.txt</Key><Size>5</Size></Contents>
+<Contents><Key>skip.txt</Key><Size>5</Size></Contents>
+<Contents><Key>../escape.txt</Key><Size>4</Size></Contents>
+</ListBucketResult>`))
+			return
+		}
+		content, ok := objects[r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected download of %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(content))
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	err = s3.DownloadPrefix(DownloadPrefixInput{
+		Bucket:       "bucket",
+		LocalDir:     dir,
+		Concurrency:  2,
+		SkipExisting: true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "../escape.txt") {
+		t.Errorf("S3.DownloadPrefix() error = %v", err)
+	}
+
+	want := map[string]string{
+		"a.txt":     "hello",
+		"sub/b.txt": "world",
+		"skip.txt":  "local",
+	}
+	for name, content := range want {
+		got, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || string(got) != content {
+			t.Errorf("S3.DownloadPrefix() %s = %q, %v, want %q", name, got, err, content)
+		}
+	}
+}
+
+func TestS3_DownloadPrefix_Slashes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gos3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	objects := map[string]string{
+		"/bucket/logs//2024/a.gz": "first",
+		"/bucket//logs/b.gz":      "second",
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("list-type") == "2" {
+			w.Write([]byte(`<ListBucketResult>
+<Contents><Key>logs//2024/a.gz</Key><Size>5</Size></Contents>
+<Contents><Key>/logs/b.gz</Key><Size>6</Size></Contents>
+</ListBucketResult>`))
+			return
+		}
+		content, ok := objects[r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected download of %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(content))
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	if err := s3.DownloadPrefix(DownloadPrefixInput{Bucket: "bucket", LocalDir: dir}); err != nil {
+		t.Fatalf("S3.DownloadPrefix() error = %v", err)
+	}
+	want := map[string]string{
+		"logs/2024/a.gz": "first",
+		"logs/b.gz":      "second",
+	}
+	for name, content := range want {
+		got, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || string(got) != content {
+			t.Errorf("S3.DownloadPrefix() %s = %q, %v, want %q", name, got, err, content)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package gos3
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestS3_ParallelDownload(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.Header.Get("Range") == "" {
+			t.Errorf("Expected a range request")
+		}
+		if r.Header.Get("Range") == "bytes=9000-9999" && strings.HasSuffix(r.URL.Path, "broken.txt") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "norange.txt") {
+			w.Write(data)
+			return
+		}
+		http.ServeContent(w, r, "test.txt", time.Time{}, bytes.NewReader(data))
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	got, err := s3.ParallelDownload(ParallelDownloadInput{
+		DownloadInput: DownloadInput{Bucket: "bucket", ObjectKey: "test.txt"},
+		Concurrency:   3,
+		ChunkSize:     1000,
+	})
+	if err != nil {
+		t.Fatalf("S3.ParallelDownload() error = %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("S3.ParallelDownload() returned %d bytes differing from the object", len(got))
+	}
+
+	_, err = s3.ParallelDownload(ParallelDownloadInput{
+		DownloadInput: DownloadInput{Bucket: "bucket", ObjectKey: "broken.txt"},
+		ChunkSize:     1000,
+	})
+	var s3Err *S3Error
+	if !errors.As(err, &s3Err) || !strings.Contains(err.Error(), "range 9000-9999") {
+		t.Errorf("S3.ParallelDownload() error = %v", err)
+	}
+
+	_, err = s3.ParallelDownload(ParallelDownloadInput{
+		DownloadInput: DownloadInput{Bucket: "bucket", ObjectKey: "norange.txt"},
+		ChunkSize:     1000,
+	})
+	if err == nil || !strings.Contains(err.Error(), "unexpected response to range request: 200") {
+		t.Errorf("S3.ParallelDownload() error = %v, want an error for the ignored range", err)
+	}
+}
@@ -0,0 +1,60 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// BucketEncryptionConfig is the server-side encryption
+// applied by default to the objects uploaded to a bucket.
+type BucketEncryptionConfig struct {
+	// SSEAlgorithm is AES256 or aws:kms, see SSES3 and SSEKMS.
+	SSEAlgorithm string `xml:"Rule>ApplyServerSideEncryptionByDefault>SSEAlgorithm"`
+	// KMSKeyID is the KMS key to use with aws:kms,
+	// if not set the AWS managed key is used.
+	KMSKeyID string `xml:"Rule>ApplyServerSideEncryptionByDefault>KMSMasterKeyID,omitempty"`
+}
+
+// serverSideEncryptionConfiguration is the XML
+// representation of a BucketEncryptionConfig.
+type serverSideEncryptionConfiguration struct {
+	XMLName xml.Name `xml:"ServerSideEncryptionConfiguration"`
+	BucketEncryptionConfig
+}
+
+// PutBucketEncryption makes a PUT call to set
+// the default encryption of a bucket to cfg.
+func (s3 *S3) PutBucketEncryption(bucket string, cfg BucketEncryptionConfig) error {
+	switch SSEType(cfg.SSEAlgorithm) {
+	case SSES3, SSEKMS:
+	default:
+		return fmt.Errorf("unknown encryption %q", cfg.SSEAlgorithm)
+	}
+	if cfg.KMSKeyID != "" && SSEType(cfg.SSEAlgorithm) != SSEKMS {
+		return errors.New("KMSKeyID requires aws:kms encryption")
+	}
+	return s3.doXML(http.MethodPut, bucket, "", "?encryption", nil, serverSideEncryptionConfiguration{
+		BucketEncryptionConfig: cfg,
+	}, nil)
+}
+
+// GetBucketEncryption makes a GET call and returns
+// the default encryption of a bucket.
+func (s3 *S3) GetBucketEncryption(bucket string) (BucketEncryptionConfig, error) {
+	var config serverSideEncryptionConfiguration
+	if err := s3.doXML(http.MethodGet, bucket, "", "?encryption", nil, nil, &config); err != nil {
+		return BucketEncryptionConfig{}, err
+	}
+	return config.BucketEncryptionConfig, nil
+}
+
+// DeleteBucketEncryption makes a DELETE call to reset the default
+// encryption of a bucket to SSE-S3, which S3 applies to all buckets.
+func (s3 *S3) DeleteBucketEncryption(bucket string) error {
+	return s3.doXML(http.MethodDelete, bucket, "", "?encryption", nil, nil, nil)
+}
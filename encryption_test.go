@@ -0,0 +1,61 @@
+package gos3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestS3_BucketEncryption(t *testing.T) {
+	var stored []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["encryption"]; !ok || r.URL.Path != "/bucket" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			stored, _ = ioutil.ReadAll(r.Body)
+		case http.MethodGet:
+			w.Write(stored)
+		case http.MethodDelete:
+			stored = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	cfg := BucketEncryptionConfig{SSEAlgorithm: "aws:kms", KMSKeyID: "arn:aws:kms:us-east-1:123456789012:key/id"}
+	if err := s3.PutBucketEncryption("bucket", cfg); err != nil {
+		t.Fatalf("S3.PutBucketEncryption() error = %v", err)
+	}
+	want := `<ServerSideEncryptionConfiguration><Rule><ApplyServerSideEncryptionByDefault><SSEAlgorithm>aws:kms</SSEAlgorithm>` +
+		`<KMSMasterKeyID>arn:aws:kms:us-east-1:123456789012:key/id</KMSMasterKeyID></ApplyServerSideEncryptionByDefault></Rule></ServerSideEncryptionConfiguration>`
+	if string(stored) != want {
+		t.Errorf("S3.PutBucketEncryption() sent = %s, want %s", stored, want)
+	}
+
+	got, err := s3.GetBucketEncryption("bucket")
+	if err != nil {
+		t.Fatalf("S3.GetBucketEncryption() error = %v", err)
+	}
+	if got != cfg {
+		t.Errorf("S3.GetBucketEncryption() = %+v, want %+v", got, cfg)
+	}
+
+	if err := s3.DeleteBucketEncryption("bucket"); err != nil {
+		t.Fatalf("S3.DeleteBucketEncryption() error = %v", err)
+	}
+
+	for _, cfg := range []BucketEncryptionConfig{
+		{SSEAlgorithm: "rot13"},
+		{SSEAlgorithm: "AES256", KMSKeyID: "key-id"},
+	} {
+		if err := s3.PutBucketEncryption("bucket", cfg); err == nil {
+			t.Errorf("S3.PutBucketEncryption(%+v) expected an error", cfg)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"errors"
+)
+
+// fipsRegions are the regions with FIPS 140-2 S3 endpoints.
+var fipsRegions = map[string]bool{
+	"us-east-1":     true,
+	"us-east-2":     true,
+	"us-west-1":     true,
+	"us-west-2":     true,
+	"ca-central-1":  true,
+	"ca-west-1":     true,
+	"us-gov-east-1": true,
+	"us-gov-west-1": true,
+}
+
+// UseAccelerate switches requests to the S3 Transfer Acceleration
+// endpoint, https://<bucket>.s3-accelerate.amazonaws.com/<key>, which
+// routes them through the nearest CloudFront edge location.
+// Acceleration must be enabled on the bucket. The endpoint only
+// supports virtual-hosted-style URLs, so an error is returned
+// unless SetURLStyle(VirtualHostedStyle) was called, or if
+// a custom endpoint is set.
+func (s3 *S3) UseAccelerate(enabled bool) (*S3, error) {
+	if enabled {
+		if s3.URLStyle != VirtualHostedStyle {
+			return s3, errors.New("transfer acceleration requires virtual-hosted-style URLs")
+		}
+		if s3.Endpoint != "" {
+			return s3, errors.New("transfer acceleration cannot be used with a custom endpoint")
+		}
+		if s3.fips {
+			return s3, errors.New("transfer acceleration cannot be used with FIPS endpoints")
+		}
+	}
+	s3.accelerate = enabled
+	return s3, nil
+}
+
+// UseDualStack switches requests to the dual-stack endpoints, eg.
+// https://s3.dualstack.<region>.amazonaws.com/<bucket>/<key>, which
+// accept both IPv4 and IPv6 connections. It applies to path-style and
+// virtual-hosted-style URLs as well as to UseAccelerate. An error is
+// returned if a custom endpoint or URIFormat is set.
+func (s3 *S3) UseDualStack(enabled bool) (*S3, error) {
+	if s3.Endpoint != "" {
+		return s3, errors.New("dual-stack endpoints cannot be used with a custom endpoint")
+	}
+	if s3.URIFormat != s3.pathURIFormat() {
+		return s3, errors.New("dual-stack endpoints cannot be used with a custom URIFormat")
+	}
+	s3.dualStack = enabled
+	s3.URIFormat = s3.pathURIFormat()
+	return s3, nil
+}
+
+// UseFIPS switches requests to the FIPS 140-2 validated endpoints,
+// eg. https://s3-fips.<region>.amazonaws.com/<bucket>/<key>, as required
+// for US government workloads. It composes with UseDualStack and
+// virtual-hosted-style URLs. FIPS endpoints only exist in some regions,
+// a warning is sent to the Logger set with SetLogger, if it implements
+// WarningLogger, when the region of the client is not one of them.
+// Enabling them returns an error if a custom endpoint or URIFormat is
+// set, or if transfer acceleration is enabled.
+func (s3 *S3) UseFIPS(enabled bool) (*S3, error) {
+	if !enabled {
+		// A custom URIFormat is kept, it was
+		// not built for the FIPS endpoints.
+		custom := s3.URIFormat != s3.pathURIFormat()
+		s3.fips = false
+		if !custom {
+			s3.URIFormat = s3.pathURIFormat()
+		}
+		return s3, nil
+	}
+
+	if s3.Endpoint != "" {
+		return s3, errors.New("FIPS endpoints cannot be used with a custom endpoint")
+	}
+	if s3.URIFormat != s3.pathURIFormat() {
+		return s3, errors.New("FIPS endpoints cannot be used with a custom URIFormat")
+	}
+	if s3.accelerate {
+		return s3, errors.New("FIPS endpoints cannot be used with transfer acceleration")
+	}
+	if !fipsRegions[s3.Region] {
+		s3.warnf("region %q has no FIPS endpoint", s3.Region)
+	}
+	s3.fips = true
+	s3.URIFormat = s3.pathURIFormat()
+	return s3, nil
+}
+
+// FIPSRegion reports whether region is known to have
+// FIPS 140-2 validated endpoints, as used by UseFIPS.
+func FIPSRegion(region string) bool {
+	return fipsRegions[region]
+}
+
+// hostFormat returns the format of the S3 host of a region,
+// which depends on UseFIPS and UseDualStack.
+func (s3 *S3) hostFormat() string {
+	host := "s3"
+	if s3.fips {
+		host += "-fips"
+	}
+	if s3.dualStack {
+		host += ".dualstack"
+	}
+	return host + ".%s.amazonaws.com"
+}
+
+// pathURIFormat returns the URIFormat of path-style
+// URLs for the endpoints in use.
+func (s3 *S3) pathURIFormat() string {
+	return "https://" + s3.hostFormat() + "/%s"
+}
+
+// accelerateHost returns the S3 Transfer Acceleration host.
+func (s3 *S3) accelerateHost() string {
+	if s3.dualStack {
+		return "s3-accelerate.dualstack.amazonaws.com"
+	}
+	return "s3-accelerate.amazonaws.com"
+}
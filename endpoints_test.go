@@ -0,0 +1,144 @@
+package gos3
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestS3_UseAccelerate(t *testing.T) {
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	if _, err := s3.UseAccelerate(true); err == nil {
+		t.Errorf("S3.UseAccelerate() expected an error with path-style URLs")
+	}
+
+	s3.SetURLStyle(VirtualHostedStyle)
+	if _, err := s3.UseAccelerate(true); err != nil {
+		t.Fatalf("S3.UseAccelerate() error = %v", err)
+	}
+	if got := s3.getURL("bucket", "xyz/image.png"); got != "https://bucket.s3-accelerate.amazonaws.com/xyz/image.png" {
+		t.Errorf("S3.getURL() = %v", got)
+	}
+
+	s3.UseAccelerate(false)
+	if got := s3.getURL("bucket", "xyz/image.png"); got != "https://bucket.s3.us-east-1.amazonaws.com/xyz/image.png" {
+		t.Errorf("S3.getURL() = %v", got)
+	}
+
+	s3.SetEndpoint("http://localhost:9000")
+	if _, err := s3.UseAccelerate(true); err == nil {
+		t.Errorf("S3.UseAccelerate() expected an error with a custom endpoint")
+	}
+}
+
+func TestS3_UseDualStack(t *testing.T) {
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	if _, err := s3.UseDualStack(true); err != nil {
+		t.Fatalf("S3.UseDualStack() error = %v", err)
+	}
+	if got := s3.getURL("bucket", "xyz/image.png"); got != "https://s3.dualstack.us-east-1.amazonaws.com/bucket/xyz/image.png" {
+		t.Errorf("S3.getURL() = %v", got)
+	}
+
+	s3.SetURLStyle(VirtualHostedStyle)
+	if got := s3.getURL("bucket", "xyz/image.png"); got != "https://bucket.s3.dualstack.us-east-1.amazonaws.com/xyz/image.png" {
+		t.Errorf("S3.getURL() = %v", got)
+	}
+
+	s3.UseAccelerate(true)
+	if got := s3.getURL("bucket", "xyz/image.png"); got != "https://bucket.s3-accelerate.dualstack.amazonaws.com/xyz/image.png" {
+		t.Errorf("S3.getURL() = %v", got)
+	}
+
+	s3.UseAccelerate(false)
+	s3.SetURLStyle(PathStyle)
+	s3.UseDualStack(false)
+	if got := s3.getURL("bucket", "xyz/image.png"); got != "https://s3.us-east-1.amazonaws.com/bucket/xyz/image.png" {
+		t.Errorf("S3.getURL() = %v", got)
+	}
+
+	s3.URIFormat = "http://%s.example.com/%s"
+	if _, err := s3.UseDualStack(true); err == nil {
+		t.Errorf("S3.UseDualStack() expected an error with a custom URIFormat")
+	}
+
+	s3 = New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint("http://localhost:9000")
+	if _, err := s3.UseDualStack(true); err == nil {
+		t.Errorf("S3.UseDualStack() expected an error with a custom endpoint")
+	}
+}
+
+func TestS3_UseFIPS(t *testing.T) {
+	s3 := New("us-gov-west-1", "AccessKey", "SuperSecretKey")
+	if _, err := s3.UseFIPS(true); err != nil {
+		t.Fatalf("S3.UseFIPS() error = %v", err)
+	}
+	if got := s3.getURL("bucket", "xyz/image.png"); got != "https://s3-fips.us-gov-west-1.amazonaws.com/bucket/xyz/image.png" {
+		t.Errorf("S3.getURL() = %v", got)
+	}
+
+	s3.UseDualStack(true)
+	s3.SetURLStyle(VirtualHostedStyle)
+	if got := s3.getURL("bucket", "xyz/image.png"); got != "https://bucket.s3-fips.dualstack.us-gov-west-1.amazonaws.com/xyz/image.png" {
+		t.Errorf("S3.getURL() = %v", got)
+	}
+
+	if _, err := s3.UseAccelerate(true); err == nil {
+		t.Errorf("S3.UseAccelerate() expected an error with FIPS endpoints")
+	}
+
+	s3.UseFIPS(false)
+	if got := s3.getURL("bucket", "xyz/image.png"); got != "https://bucket.s3.dualstack.us-gov-west-1.amazonaws.com/xyz/image.png" {
+		t.Errorf("S3.getURL() = %v", got)
+	}
+}
+
+func TestFIPSRegion(t *testing.T) {
+	for region, want := range map[string]bool{
+		"us-east-1":     true,
+		"us-gov-west-1": true,
+		"eu-west-1":     false,
+		"":              false,
+	} {
+		if got := FIPSRegion(region); got != want {
+			t.Errorf("FIPSRegion(%q) = %v, want %v", region, got, want)
+		}
+	}
+}
+
+func TestS3_UseFIPS_Warning(t *testing.T) {
+	var buf bytes.Buffer
+	s3 := New("eu-west-1", "AccessKey", "SuperSecretKey")
+	s3.SetLogger(dumpLogger{w: &buf})
+	if _, err := s3.UseFIPS(true); err != nil {
+		t.Fatalf("S3.UseFIPS() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `region "eu-west-1" has no FIPS endpoint`) {
+		t.Errorf("S3.UseFIPS() logged %q, want a warning", buf.String())
+	}
+
+	buf.Reset()
+	s3 = New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetLogger(dumpLogger{w: &buf})
+	if _, err := s3.UseFIPS(true); err != nil {
+		t.Fatalf("S3.UseFIPS() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("S3.UseFIPS() logged %q, want nothing", buf.String())
+	}
+}
+
+func TestS3_UseFIPS_Disable(t *testing.T) {
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint("http://localhost:9000")
+	if _, err := s3.UseFIPS(true); err == nil {
+		t.Errorf("S3.UseFIPS() expected an error with a custom endpoint")
+	}
+	if _, err := s3.UseFIPS(false); err != nil {
+		t.Errorf("S3.UseFIPS() error = %v", err)
+	}
+	if got := s3.getURL("bucket", "image.png"); got != "http://localhost:9000/bucket/image.png" {
+		t.Errorf("S3.getURL() = %v", got)
+	}
+}
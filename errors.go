@@ -0,0 +1,129 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// ErrMetadataTooLarge is returned when the user-defined
+// metadata of an upload exceeds 2 KB.
+var ErrMetadataTooLarge = errors.New("metadata exceeds 2 KB")
+
+// ErrChecksumMismatch is returned when the checksum of
+// a downloaded object differs from the expected one.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ErrPresignedURLExpired is returned by ValidatePresignedURL
+// for a Presigned URL which is no longer valid.
+var ErrPresignedURLExpired = errors.New("presigned url expired")
+
+// ErrInvalidARN is returned for a request to a
+// bucket given as a malformed access point ARN.
+var ErrInvalidARN = errors.New("invalid arn")
+
+// ErrNoMorePages is returned by Paginator.NextPage
+// once the last page has been fetched.
+var ErrNoMorePages = errors.New("no more pages")
+
+// ErrInvalidObjectKey is returned by ValidateObjectKey, and by
+// the calls checking the key of an object before any request.
+var ErrInvalidObjectKey = errors.New("invalid object key")
+
+var (
+	// ErrNotFound matches any S3Error with a 404 status code,
+	// including responses to HEAD requests that carry no body.
+	ErrNotFound = errors.New("not found")
+	// ErrNoSuchKey matches an S3Error with the NoSuchKey code.
+	ErrNoSuchKey = errors.New("no such key")
+	// ErrNoSuchBucket matches an S3Error with the NoSuchBucket code.
+	ErrNoSuchBucket = errors.New("no such bucket")
+	// ErrAccessDenied matches an S3Error with the AccessDenied code.
+	ErrAccessDenied = errors.New("access denied")
+	// ErrRangeNotSatisfiable matches an S3Error with a 416 status code,
+	// returned when the requested range is outside of the object.
+	ErrRangeNotSatisfiable = errors.New("range not satisfiable")
+	// ErrNotModified matches an S3Error with a 304 status code,
+	// returned by a conditional GET when the cached copy is current.
+	ErrNotModified = errors.New("not modified")
+	// ErrVersioningNotEnabled matches an S3Error returned for
+	// a configuration which requires versioning, such as
+	// replication, on a bucket where it is not enabled.
+	ErrVersioningNotEnabled = errors.New("versioning not enabled")
+)
+
+// S3Error is returned when S3 responds with an unexpected status code.
+// The fields are parsed from the XML error document sent by S3,
+// except for HEAD requests where only StatusCode is available.
+// Use errors.As to access them, or errors.Is with one of
+// the exported sentinel errors to check for common codes.
+type S3Error struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+	Resource   string `xml:"Resource"`
+	RequestID  string `xml:"RequestId"`
+	HostID     string `xml:"HostId"`
+}
+
+func (e *S3Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("status code: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("status code: %d %s: %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Code, e.Message)
+}
+
+// Is reports whether the error matches one of
+// the exported sentinel errors.
+func (e *S3Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrNoSuchKey:
+		return e.Code == "NoSuchKey"
+	case ErrNoSuchBucket:
+		return e.Code == "NoSuchBucket"
+	case ErrAccessDenied:
+		return e.Code == "AccessDenied"
+	case ErrRangeNotSatisfiable:
+		return e.StatusCode == http.StatusRequestedRangeNotSatisfiable
+	case ErrNotModified:
+		return e.StatusCode == http.StatusNotModified
+	case ErrVersioningNotEnabled:
+		return e.Code == "InvalidRequest" && strings.Contains(e.Message, "Versioning must be 'Enabled'")
+	}
+	return false
+}
+
+// newResponseError reads the body of an unsuccessful
+// response and returns it as an *S3Error.
+func newResponseError(res *http.Response) error {
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	return parseS3Error(res.StatusCode, res.Header, data)
+}
+
+func parseS3Error(statusCode int, header http.Header, data []byte) *S3Error {
+	e := &S3Error{StatusCode: statusCode}
+	if err := xml.Unmarshal(data, e); err != nil {
+		// Not an XML error document, keep
+		// whatever was sent as the message.
+		e.Message = strings.TrimSpace(string(data))
+	}
+	md := newRequestMetadata(header)
+	if e.RequestID == "" {
+		e.RequestID = md.RequestID
+	}
+	if e.HostID == "" {
+		e.HostID = md.HostID
+	}
+	return e
+}
@@ -0,0 +1,51 @@
+package gos3
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestS3_FileDownload_S3Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-amz-request-id", "header-request-id")
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>
+<Error>
+  <Code>NoSuchKey</Code>
+  <Message>The resource you requested does not exist</Message>
+  <Resource>/bucket/missing.txt</Resource>
+  <RequestId>4442587FB7D0A2F9</RequestId>
+</Error>`)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	_, err := s3.FileDownload(DownloadInput{Bucket: "bucket", ObjectKey: "missing.txt"})
+	var s3err *S3Error
+	if !errors.As(err, &s3err) {
+		t.Fatalf("S3.FileDownload() error = %v, want *S3Error", err)
+	}
+	if s3err.Code != "NoSuchKey" || s3err.RequestID != "4442587FB7D0A2F9" || s3err.Resource != "/bucket/missing.txt" {
+		t.Errorf("S3.FileDownload() error = %+v", s3err)
+	}
+	if !errors.Is(err, ErrNoSuchKey) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("S3.FileDownload() error = %v, want it to match ErrNoSuchKey and ErrNotFound", err)
+	}
+	if errors.Is(err, ErrAccessDenied) || errors.Is(err, ErrNoSuchBucket) {
+		t.Errorf("S3.FileDownload() error = %v, matches an unrelated sentinel", err)
+	}
+}
+
+func TestParseS3Error_NoBody(t *testing.T) {
+	header := http.Header{}
+	header.Set("x-amz-request-id", "header-request-id")
+	err := parseS3Error(http.StatusForbidden, header, nil)
+	if err.RequestID != "header-request-id" || err.Error() != "status code: 403 Forbidden" {
+		t.Errorf("parseS3Error() = %+v, %v", err, err)
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/animber-coder/gos3
 
-go 1.13
+go 1.18
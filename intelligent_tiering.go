@@ -0,0 +1,122 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+)
+
+// Access tiers of a Tiering, to which objects not
+// accessed for a number of days are moved.
+const (
+	AccessTierArchive     = "ARCHIVE_ACCESS"
+	AccessTierDeepArchive = "DEEP_ARCHIVE_ACCESS"
+)
+
+// IntelligentTieringConfig configures the archive access tiers of
+// the objects of a bucket in the INTELLIGENT_TIERING storage class.
+type IntelligentTieringConfig struct {
+	ID string
+	// Status is Enabled or Disabled.
+	Status   string
+	Filter   IntelligentTieringFilter
+	Tierings []Tiering
+}
+
+// IntelligentTieringFilter limits a configuration to the
+// objects whose key starts with Prefix.
+type IntelligentTieringFilter struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// Tiering moves the objects not accessed for Days
+// days to AccessTier, one of the AccessTier constants.
+type Tiering struct {
+	Days       int    `xml:"Days"`
+	AccessTier string `xml:"AccessTier"`
+}
+
+// intelligentTieringConfiguration is the XML representation of
+// an IntelligentTieringConfig. The filter is omitted when empty.
+type intelligentTieringConfiguration struct {
+	XMLName  xml.Name                  `xml:"IntelligentTieringConfiguration"`
+	ID       string                    `xml:"Id"`
+	Filter   *IntelligentTieringFilter `xml:"Filter"`
+	Status   string                    `xml:"Status"`
+	Tierings []Tiering                 `xml:"Tiering"`
+}
+
+// config returns the IntelligentTieringConfig represented by c.
+func (c intelligentTieringConfiguration) config() IntelligentTieringConfig {
+	cfg := IntelligentTieringConfig{ID: c.ID, Status: c.Status, Tierings: c.Tierings}
+	if c.Filter != nil {
+		cfg.Filter = *c.Filter
+	}
+	return cfg
+}
+
+// listIntelligentTieringResult receives the
+// ListBucketIntelligentTieringConfigurationsOutput XML.
+type listIntelligentTieringResult struct {
+	Configs               []intelligentTieringConfiguration `xml:"IntelligentTieringConfiguration"`
+	IsTruncated           bool                              `xml:"IsTruncated"`
+	NextContinuationToken string                            `xml:"NextContinuationToken"`
+}
+
+// intelligentTieringQuery returns the query selecting the
+// Intelligent-Tiering configuration id of a bucket.
+func intelligentTieringQuery(id string) string {
+	return "?intelligent-tiering&id=" + url.QueryEscape(id)
+}
+
+// PutBucketIntelligentTiering makes a PUT call to create or replace
+// the Intelligent-Tiering configuration cfg.ID of a bucket.
+func (s3 *S3) PutBucketIntelligentTiering(bucket string, cfg IntelligentTieringConfig) error {
+	config := intelligentTieringConfiguration{ID: cfg.ID, Status: cfg.Status, Tierings: cfg.Tierings}
+	if cfg.Filter.Prefix != "" {
+		config.Filter = &cfg.Filter
+	}
+	return s3.doXML(http.MethodPut, bucket, "", intelligentTieringQuery(cfg.ID), nil, config, nil)
+}
+
+// GetBucketIntelligentTiering makes a GET call and returns the
+// Intelligent-Tiering configuration id of a bucket. If there is
+// no such configuration, the returned error matches ErrNotFound.
+func (s3 *S3) GetBucketIntelligentTiering(bucket, id string) (IntelligentTieringConfig, error) {
+	var config intelligentTieringConfiguration
+	if err := s3.doXML(http.MethodGet, bucket, "", intelligentTieringQuery(id), nil, nil, &config); err != nil {
+		return IntelligentTieringConfig{}, err
+	}
+	return config.config(), nil
+}
+
+// DeleteBucketIntelligentTiering makes a DELETE call to remove
+// the Intelligent-Tiering configuration id of a bucket.
+func (s3 *S3) DeleteBucketIntelligentTiering(bucket, id string) error {
+	return s3.doXML(http.MethodDelete, bucket, "", intelligentTieringQuery(id), nil, nil, nil)
+}
+
+// ListBucketIntelligentTieringConfigurations makes GET calls to list
+// all the Intelligent-Tiering configurations of a bucket.
+func (s3 *S3) ListBucketIntelligentTieringConfigurations(bucket string) ([]IntelligentTieringConfig, error) {
+	var configs []IntelligentTieringConfig
+	query := url.Values{}
+	query.Set("intelligent-tiering", "")
+	for {
+		var result listIntelligentTieringResult
+		if err := s3.doXML(http.MethodGet, bucket, "", "?"+query.Encode(), nil, nil, &result); err != nil {
+			return nil, err
+		}
+		for _, c := range result.Configs {
+			configs = append(configs, c.config())
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return configs, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
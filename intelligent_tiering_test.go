@@ -0,0 +1,91 @@
+package gos3
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestS3_BucketIntelligentTiering(t *testing.T) {
+	stored := map[string][]byte{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if _, ok := q["intelligent-tiering"]; !ok || r.URL.Path != "/bucket" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		id := q.Get("id")
+		switch {
+		case r.Method == http.MethodPut:
+			stored[id], _ = ioutil.ReadAll(r.Body)
+		case r.Method == http.MethodGet && id != "":
+			if stored[id] == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(stored[id])
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`<ListBucketIntelligentTieringConfigurationsOutput><IsTruncated>false</IsTruncated>`))
+			for _, config := range stored {
+				w.Write(config)
+			}
+			w.Write([]byte(`</ListBucketIntelligentTieringConfigurationsOutput>`))
+		case r.Method == http.MethodDelete:
+			delete(stored, id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	cfg := IntelligentTieringConfig{
+		ID:     "archive",
+		Status: "Enabled",
+		Tierings: []Tiering{
+			{Days: 90, AccessTier: AccessTierArchive},
+			{Days: 180, AccessTier: AccessTierDeepArchive},
+		},
+	}
+	if err := s3.PutBucketIntelligentTiering("bucket", cfg); err != nil {
+		t.Fatalf("S3.PutBucketIntelligentTiering() error = %v", err)
+	}
+	want := `<IntelligentTieringConfiguration><Id>archive</Id><Status>Enabled</Status>` +
+		`<Tiering><Days>90</Days><AccessTier>ARCHIVE_ACCESS</AccessTier></Tiering>` +
+		`<Tiering><Days>180</Days><AccessTier>DEEP_ARCHIVE_ACCESS</AccessTier></Tiering></IntelligentTieringConfiguration>`
+	if string(stored["archive"]) != want {
+		t.Errorf("S3.PutBucketIntelligentTiering() sent = %s, want %s", stored["archive"], want)
+	}
+
+	got, err := s3.GetBucketIntelligentTiering("bucket", "archive")
+	if err != nil {
+		t.Fatalf("S3.GetBucketIntelligentTiering() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("S3.GetBucketIntelligentTiering() = %+v, want %+v", got, cfg)
+	}
+
+	logs := IntelligentTieringConfig{
+		ID:       "logs",
+		Status:   "Disabled",
+		Filter:   IntelligentTieringFilter{Prefix: "logs/"},
+		Tierings: []Tiering{{Days: 365, AccessTier: AccessTierDeepArchive}},
+	}
+	if err := s3.PutBucketIntelligentTiering("bucket", logs); err != nil {
+		t.Fatalf("S3.PutBucketIntelligentTiering() error = %v", err)
+	}
+	configs, err := s3.ListBucketIntelligentTieringConfigurations("bucket")
+	if err != nil || len(configs) != 2 {
+		t.Errorf("S3.ListBucketIntelligentTieringConfigurations() = %+v, %v", configs, err)
+	}
+
+	if err := s3.DeleteBucketIntelligentTiering("bucket", "logs"); err != nil {
+		t.Fatalf("S3.DeleteBucketIntelligentTiering() error = %v", err)
+	}
+	if _, err := s3.GetBucketIntelligentTiering("bucket", "logs"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("S3.GetBucketIntelligentTiering() error = %v, want ErrNotFound", err)
+	}
+}
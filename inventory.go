@@ -0,0 +1,187 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+)
+
+// Formats of the inventory reports.
+const (
+	InventoryFormatCSV     = "CSV"
+	InventoryFormatORC     = "ORC"
+	InventoryFormatParquet = "Parquet"
+)
+
+// Frequencies of the inventory reports.
+const (
+	InventoryFrequencyDaily  = "Daily"
+	InventoryFrequencyWeekly = "Weekly"
+)
+
+// Object versions listed in the inventory reports.
+const (
+	InventoryVersionsAll     = "All"
+	InventoryVersionsCurrent = "Current"
+)
+
+// InventoryConfig configures the reports listing
+// the objects of a bucket delivered by S3 Inventory.
+type InventoryConfig struct {
+	ID      string
+	Enabled bool
+
+	// DestinationBucket is the ARN of the bucket the reports are
+	// written to, eg. arn:aws:s3:::reports, under DestinationPrefix.
+	// DestinationAccountID is the account owning it, if not the
+	// one owning the source bucket.
+	DestinationBucket    string
+	DestinationPrefix    string
+	DestinationAccountID string
+	// Format is one of the InventoryFormat constants.
+	Format string
+
+	// Prefix limits the reports to the objects
+	// whose key starts with it.
+	Prefix string
+	// IncludedObjectVersions is one of the InventoryVersions constants.
+	IncludedObjectVersions string
+	// OptionalFields are added to the reports, eg. Size,
+	// LastModifiedDate, StorageClass or ETag.
+	OptionalFields []string
+	// Frequency is one of the InventoryFrequency constants.
+	Frequency string
+}
+
+// inventoryConfiguration is the XML
+// representation of an InventoryConfig.
+type inventoryConfiguration struct {
+	XMLName     xml.Name `xml:"InventoryConfiguration"`
+	Destination struct {
+		AccountID string `xml:"AccountId,omitempty"`
+		Bucket    string `xml:"Bucket"`
+		Format    string `xml:"Format"`
+		Prefix    string `xml:"Prefix,omitempty"`
+	} `xml:"Destination>S3BucketDestination"`
+	IsEnabled              bool                     `xml:"IsEnabled"`
+	Filter                 *inventoryFilter         `xml:"Filter"`
+	ID                     string                   `xml:"Id"`
+	IncludedObjectVersions string                   `xml:"IncludedObjectVersions"`
+	OptionalFields         *inventoryOptionalFields `xml:"OptionalFields"`
+	Frequency              string                   `xml:"Schedule>Frequency"`
+}
+
+// inventoryFilter and inventoryOptionalFields are pointers in
+// inventoryConfiguration, so that they are omitted when empty.
+type inventoryFilter struct {
+	Prefix string `xml:"Prefix"`
+}
+
+type inventoryOptionalFields struct {
+	Fields []string `xml:"Field"`
+}
+
+// newInventoryConfiguration returns the XML representation of cfg.
+func newInventoryConfiguration(cfg InventoryConfig) inventoryConfiguration {
+	c := inventoryConfiguration{
+		IsEnabled:              cfg.Enabled,
+		ID:                     cfg.ID,
+		IncludedObjectVersions: cfg.IncludedObjectVersions,
+		Frequency:              cfg.Frequency,
+	}
+	c.Destination.AccountID = cfg.DestinationAccountID
+	c.Destination.Bucket = cfg.DestinationBucket
+	c.Destination.Format = cfg.Format
+	c.Destination.Prefix = cfg.DestinationPrefix
+	if cfg.Prefix != "" {
+		c.Filter = &inventoryFilter{Prefix: cfg.Prefix}
+	}
+	if len(cfg.OptionalFields) > 0 {
+		c.OptionalFields = &inventoryOptionalFields{Fields: cfg.OptionalFields}
+	}
+	return c
+}
+
+// config returns the InventoryConfig represented by c.
+func (c inventoryConfiguration) config() InventoryConfig {
+	cfg := InventoryConfig{
+		ID:                     c.ID,
+		Enabled:                c.IsEnabled,
+		DestinationBucket:      c.Destination.Bucket,
+		DestinationPrefix:      c.Destination.Prefix,
+		DestinationAccountID:   c.Destination.AccountID,
+		Format:                 c.Destination.Format,
+		IncludedObjectVersions: c.IncludedObjectVersions,
+		Frequency:              c.Frequency,
+	}
+	if c.Filter != nil {
+		cfg.Prefix = c.Filter.Prefix
+	}
+	if c.OptionalFields != nil {
+		cfg.OptionalFields = c.OptionalFields.Fields
+	}
+	return cfg
+}
+
+// listInventoryConfigurationsResult receives
+// the ListInventoryConfigurationsResult XML.
+type listInventoryConfigurationsResult struct {
+	Configs               []inventoryConfiguration `xml:"InventoryConfiguration"`
+	IsTruncated           bool                     `xml:"IsTruncated"`
+	NextContinuationToken string                   `xml:"NextContinuationToken"`
+}
+
+// inventoryQuery returns the query selecting the
+// inventory configuration id of a bucket.
+func inventoryQuery(id string) string {
+	return "?inventory&id=" + url.QueryEscape(id)
+}
+
+// PutBucketInventory makes a PUT call to create or replace the
+// inventory configuration id of a bucket. The ID of cfg is set to id.
+func (s3 *S3) PutBucketInventory(bucket, id string, cfg InventoryConfig) error {
+	cfg.ID = id
+	return s3.doXML(http.MethodPut, bucket, "", inventoryQuery(id), nil, newInventoryConfiguration(cfg), nil)
+}
+
+// GetBucketInventory makes a GET call and returns the inventory
+// configuration id of a bucket. If there is no such configuration,
+// the returned error matches ErrNotFound.
+func (s3 *S3) GetBucketInventory(bucket, id string) (InventoryConfig, error) {
+	var config inventoryConfiguration
+	if err := s3.doXML(http.MethodGet, bucket, "", inventoryQuery(id), nil, nil, &config); err != nil {
+		return InventoryConfig{}, err
+	}
+	return config.config(), nil
+}
+
+// DeleteBucketInventory makes a DELETE call to remove
+// the inventory configuration id of a bucket.
+func (s3 *S3) DeleteBucketInventory(bucket, id string) error {
+	return s3.doXML(http.MethodDelete, bucket, "", inventoryQuery(id), nil, nil, nil)
+}
+
+// ListBucketInventoryConfigurations makes GET calls to list
+// all the inventory configurations of a bucket.
+func (s3 *S3) ListBucketInventoryConfigurations(bucket string) ([]InventoryConfig, error) {
+	var configs []InventoryConfig
+	query := url.Values{}
+	query.Set("inventory", "")
+	for {
+		var result listInventoryConfigurationsResult
+		if err := s3.doXML(http.MethodGet, bucket, "", "?"+query.Encode(), nil, nil, &result); err != nil {
+			return nil, err
+		}
+		for _, c := range result.Configs {
+			configs = append(configs, c.config())
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return configs, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
@@ -0,0 +1,87 @@
+package gos3
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestS3_BucketInventory(t *testing.T) {
+	stored := map[string][]byte{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if _, ok := q["inventory"]; !ok || r.URL.Path != "/bucket" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		id := q.Get("id")
+		switch {
+		case r.Method == http.MethodPut:
+			stored[id], _ = ioutil.ReadAll(r.Body)
+		case r.Method == http.MethodGet && id != "":
+			if stored[id] == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(stored[id])
+		case r.Method == http.MethodGet && q.Get("continuation-token") == "":
+			w.Write([]byte(`<ListInventoryConfigurationsResult><IsTruncated>true</IsTruncated><NextContinuationToken>next</NextContinuationToken>`))
+			w.Write(stored["daily"])
+			w.Write([]byte(`</ListInventoryConfigurationsResult>`))
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`<ListInventoryConfigurationsResult><IsTruncated>false</IsTruncated>` +
+				`<InventoryConfiguration><Id>weekly</Id><IsEnabled>false</IsEnabled></InventoryConfiguration></ListInventoryConfigurationsResult>`))
+		case r.Method == http.MethodDelete:
+			delete(stored, id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	cfg := InventoryConfig{
+		DestinationBucket:      "arn:aws:s3:::reports",
+		Format:                 InventoryFormatCSV,
+		Enabled:                true,
+		IncludedObjectVersions: InventoryVersionsCurrent,
+		OptionalFields:         []string{"Size", "ETag"},
+		Frequency:              InventoryFrequencyDaily,
+	}
+	if err := s3.PutBucketInventory("bucket", "daily", cfg); err != nil {
+		t.Fatalf("S3.PutBucketInventory() error = %v", err)
+	}
+	want := `<InventoryConfiguration><Destination><S3BucketDestination><Bucket>arn:aws:s3:::reports</Bucket><Format>CSV</Format></S3BucketDestination></Destination>` +
+		`<IsEnabled>true</IsEnabled><Id>daily</Id><IncludedObjectVersions>Current</IncludedObjectVersions>` +
+		`<OptionalFields><Field>Size</Field><Field>ETag</Field></OptionalFields><Schedule><Frequency>Daily</Frequency></Schedule></InventoryConfiguration>`
+	if string(stored["daily"]) != want {
+		t.Errorf("S3.PutBucketInventory() sent = %s, want %s", stored["daily"], want)
+	}
+
+	cfg.ID = "daily"
+	got, err := s3.GetBucketInventory("bucket", "daily")
+	if err != nil {
+		t.Fatalf("S3.GetBucketInventory() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("S3.GetBucketInventory() = %+v, want %+v", got, cfg)
+	}
+
+	configs, err := s3.ListBucketInventoryConfigurations("bucket")
+	if err != nil {
+		t.Fatalf("S3.ListBucketInventoryConfigurations() error = %v", err)
+	}
+	if len(configs) != 2 || configs[0].ID != "daily" || configs[1].ID != "weekly" {
+		t.Errorf("S3.ListBucketInventoryConfigurations() = %+v", configs)
+	}
+
+	if err := s3.DeleteBucketInventory("bucket", "daily"); err != nil {
+		t.Fatalf("S3.DeleteBucketInventory() error = %v", err)
+	}
+	if _, err := s3.GetBucketInventory("bucket", "daily"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("S3.GetBucketInventory() error = %v, want ErrNotFound", err)
+	}
+}
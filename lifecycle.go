@@ -0,0 +1,109 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// LifecycleRule expires or transitions the objects
+// whose key starts with Prefix.
+type LifecycleRule struct {
+	ID string
+	// Status is either "Enabled" or "Disabled".
+	Status string
+	Prefix string
+
+	// ExpirationDays is the number of days after creation
+	// when objects are deleted, zero disables expiration.
+	ExpirationDays int
+
+	// TransitionDays is the number of days after creation
+	// when objects are moved to TransitionStorageClass,
+	// zero disables the transition.
+	TransitionDays         int
+	TransitionStorageClass string
+}
+
+// lifecycleConfiguration is the XML representation of
+// the lifecycle rules of a bucket.
+type lifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration"`
+	Rules   []lifecycleRule `xml:"Rule"`
+}
+
+type lifecycleRule struct {
+	ID string `xml:"ID,omitempty"`
+	// Filter is always sent, as S3 requires either it or
+	// the legacy Prefix element, which is only read.
+	Filter struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"Filter"`
+	Prefix     string               `xml:"Prefix,omitempty"`
+	Status     string               `xml:"Status"`
+	Expiration *lifecycleExpiration `xml:"Expiration"`
+	Transition *lifecycleTransition `xml:"Transition"`
+}
+
+type lifecycleExpiration struct {
+	Days int `xml:"Days"`
+}
+
+type lifecycleTransition struct {
+	Days         int    `xml:"Days"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+// PutBucketLifecycle makes a PUT call to replace
+// the lifecycle rules of a bucket with rules.
+func (s3 *S3) PutBucketLifecycle(bucket string, rules []LifecycleRule) error {
+	var config lifecycleConfiguration
+	for _, r := range rules {
+		rule := lifecycleRule{ID: r.ID, Status: r.Status}
+		rule.Filter.Prefix = r.Prefix
+		if r.ExpirationDays > 0 {
+			rule.Expiration = &lifecycleExpiration{Days: r.ExpirationDays}
+		}
+		if r.TransitionDays > 0 {
+			rule.Transition = &lifecycleTransition{
+				Days:         r.TransitionDays,
+				StorageClass: r.TransitionStorageClass,
+			}
+		}
+		config.Rules = append(config.Rules, rule)
+	}
+	return s3.doXML(http.MethodPut, bucket, "", "?lifecycle", nil, config, nil)
+}
+
+// GetBucketLifecycle makes a GET call and returns the lifecycle
+// rules of a bucket. If the bucket has none, the returned
+// error matches ErrNotFound.
+func (s3 *S3) GetBucketLifecycle(bucket string) ([]LifecycleRule, error) {
+	var config lifecycleConfiguration
+	if err := s3.doXML(http.MethodGet, bucket, "", "?lifecycle", nil, nil, &config); err != nil {
+		return nil, err
+	}
+
+	var rules []LifecycleRule
+	for _, r := range config.Rules {
+		rule := LifecycleRule{
+			ID:     r.ID,
+			Status: r.Status,
+			Prefix: r.Filter.Prefix,
+		}
+		if rule.Prefix == "" {
+			rule.Prefix = r.Prefix
+		}
+		if r.Expiration != nil {
+			rule.ExpirationDays = r.Expiration.Days
+		}
+		if r.Transition != nil {
+			rule.TransitionDays = r.Transition.Days
+			rule.TransitionStorageClass = r.Transition.StorageClass
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,48 @@
+package gos3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestS3_BucketLifecycle(t *testing.T) {
+	var stored []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["lifecycle"]; !ok || r.URL.Path != "/bucket" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			stored, _ = ioutil.ReadAll(r.Body)
+		case http.MethodGet:
+			w.Write(stored)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	rules := []LifecycleRule{
+		{ID: "expire-logs", Status: "Enabled", Prefix: "logs/", ExpirationDays: 30},
+		{ID: "archive", Status: "Enabled", Prefix: "data/", TransitionDays: 90, TransitionStorageClass: StorageClassGlacier},
+	}
+	if err := s3.PutBucketLifecycle("bucket", rules); err != nil {
+		t.Fatalf("S3.PutBucketLifecycle() error = %v", err)
+	}
+	if !strings.Contains(string(stored), "<Filter><Prefix>logs/</Prefix></Filter>") || strings.Count(string(stored), "<Expiration>") != 1 {
+		t.Errorf("S3.PutBucketLifecycle() sent = %s", stored)
+	}
+
+	got, err := s3.GetBucketLifecycle("bucket")
+	if err != nil {
+		t.Fatalf("S3.GetBucketLifecycle() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, rules) {
+		t.Errorf("S3.GetBucketLifecycle() = %+v, want %+v", got, rules)
+	}
+}
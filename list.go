@@ -0,0 +1,213 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ListInput is passed to ListObjects as a parameter.
+type ListInput struct {
+	// essential fields
+	Bucket string
+
+	// optional fields
+	Prefix            string
+	Delimiter         string
+	MaxKeys           int
+	ContinuationToken string
+}
+
+// ObjectInfo describes an object returned by ListObjects.
+type ObjectInfo struct {
+	Key          string    `xml:"Key"`
+	LastModified time.Time `xml:"LastModified"`
+	ETag         string    `xml:"ETag"`
+	Size         int64     `xml:"Size"`
+	StorageClass string    `xml:"StorageClass"`
+}
+
+// ListOutput is returned by ListObjects.
+type ListOutput struct {
+	Objects               []ObjectInfo
+	CommonPrefixes        []string
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// listBucketResult receives the ListBucketResult XML
+// returned by the ListObjectsV2 API.
+type listBucketResult struct {
+	Contents       []ObjectInfo `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// ListObjects makes a GET call to list up to in.MaxKeys objects
+// (1000 by default) in a bucket. If the output is truncated,
+// pass NextContinuationToken as in.ContinuationToken to fetch
+// the next page.
+func (s3 *S3) ListObjects(in ListInput) (ListOutput, error) {
+	return s3.ListObjectsWithContext(context.Background(), in)
+}
+
+// ListObjectsWithContext is like ListObjects but the
+// request is cancelled when ctx is done.
+func (s3 *S3) ListObjectsWithContext(ctx context.Context, in ListInput) (ListOutput, error) {
+	query := url.Values{}
+	query.Set("list-type", "2")
+	if in.Prefix != "" {
+		query.Set("prefix", in.Prefix)
+	}
+	if in.Delimiter != "" {
+		query.Set("delimiter", in.Delimiter)
+	}
+	if in.MaxKeys > 0 {
+		query.Set("max-keys", strconv.Itoa(in.MaxKeys))
+	}
+	if in.ContinuationToken != "" {
+		query.Set("continuation-token", in.ContinuationToken)
+	}
+
+	req, err := s3.newRequest(
+		ctx, http.MethodGet, in.Bucket, "", "?"+query.Encode(), nil,
+	)
+	if err != nil {
+		return ListOutput{}, err
+	}
+
+	if err := s3.signRequest(req); err != nil {
+		return ListOutput{}, err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return ListOutput{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return ListOutput{}, newResponseError(res)
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return ListOutput{}, err
+	}
+
+	var result listBucketResult
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return ListOutput{}, err
+	}
+
+	out := ListOutput{
+		Objects:               result.Contents,
+		IsTruncated:           result.IsTruncated,
+		NextContinuationToken: result.NextContinuationToken,
+	}
+	for _, p := range result.CommonPrefixes {
+		out.CommonPrefixes = append(out.CommonPrefixes, p.Prefix)
+	}
+	return out, nil
+}
+
+// ListAllObjects calls ListObjects until the listing is no longer
+// truncated and returns the objects of every page.
+func (s3 *S3) ListAllObjects(in ListInput) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	for {
+		out, err := s3.ListObjects(in)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, out.Objects...)
+
+		if !out.IsTruncated || out.NextContinuationToken == "" {
+			return objects, nil
+		}
+		in.ContinuationToken = out.NextContinuationToken
+	}
+}
+
+// StreamListObjects calls ListObjectsWithContext in the background until the
+// listing is no longer truncated, sending each object on the first
+// channel, which is closed once done. At most one error is sent on
+// the second channel, which is closed after the first one, if a call
+// fails or ctx is canceled. Unlike ListAllObjects, the objects are not
+// held in memory, making it suitable for buckets of millions of objects.
+func (s3 *S3) StreamListObjects(ctx context.Context, in ListInput) (<-chan ObjectInfo, <-chan error) {
+	objects := make(chan ObjectInfo)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(objects)
+		for {
+			if err := ctx.Err(); err != nil {
+				errs <- err
+				return
+			}
+			out, err := s3.ListObjectsWithContext(ctx, in)
+			if err != nil {
+				errs <- err
+				return
+			}
+			for _, obj := range out.Objects {
+				select {
+				case objects <- obj:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+
+			if !out.IsTruncated || out.NextContinuationToken == "" {
+				return
+			}
+			in.ContinuationToken = out.NextContinuationToken
+		}
+	}()
+	return objects, errs
+}
+
+// BucketUsage calls ListObjects until the listing is no longer
+// truncated and returns the total size and number of the objects
+// whose key starts with prefix, in the whole bucket if empty.
+// Only a page of objects is held in memory at once.
+func (s3 *S3) BucketUsage(bucket, prefix string) (totalBytes, objectCount int64, err error) {
+	return s3.BucketUsageWithProgress(bucket, prefix, nil)
+}
+
+// BucketUsageWithProgress is like BucketUsage but progress, if not
+// nil, is called after each page with the totals so far, which
+// helps to follow the listing of large buckets.
+func (s3 *S3) BucketUsageWithProgress(bucket, prefix string, progress func(totalBytes, objectCount int64)) (totalBytes, objectCount int64, err error) {
+	in := ListInput{Bucket: bucket, Prefix: prefix}
+	for {
+		out, err := s3.ListObjects(in)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, obj := range out.Objects {
+			totalBytes += obj.Size
+		}
+		objectCount += int64(len(out.Objects))
+		if progress != nil {
+			progress(totalBytes, objectCount)
+		}
+
+		if !out.IsTruncated || out.NextContinuationToken == "" {
+			return totalBytes, objectCount, nil
+		}
+		in.ContinuationToken = out.NextContinuationToken
+	}
+}
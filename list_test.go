@@ -0,0 +1,181 @@
+package gos3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestS3_ListAllObjects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("list-type") != "2" || q.Get("prefix") != "xyz/" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if q.Get("continuation-token") == "" {
+			io.WriteString(w, `<ListBucketResult>
+				<Contents><Key>xyz/a.txt</Key><LastModified>2013-05-24T00:00:00.000Z</LastModified><ETag>"a"</ETag><Size>1</Size></Contents>
+				<CommonPrefixes><Prefix>xyz/sub/</Prefix></CommonPrefixes>
+				<IsTruncated>true</IsTruncated>
+				<NextContinuationToken>next</NextContinuationToken>
+			</ListBucketResult>`)
+			return
+		}
+		io.WriteString(w, `<ListBucketResult>
+			<Contents><Key>xyz/b.txt</Key><ETag>"b"</ETag><Size>2</Size></Contents>
+			<IsTruncated>false</IsTruncated>
+		</ListBucketResult>`)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	out, err := s3.ListObjects(ListInput{Bucket: "bucket", Prefix: "xyz/", Delimiter: "/"})
+	if err != nil {
+		t.Fatalf("S3.ListObjects() error = %v", err)
+	}
+	if !out.IsTruncated || out.NextContinuationToken != "next" || len(out.CommonPrefixes) != 1 {
+		t.Errorf("S3.ListObjects() got = %+v", out)
+	}
+	if len(out.Objects) != 1 || out.Objects[0].LastModified.IsZero() {
+		t.Errorf("S3.ListObjects() got = %+v", out.Objects)
+	}
+
+	objects, err := s3.ListAllObjects(ListInput{Bucket: "bucket", Prefix: "xyz/"})
+	if err != nil {
+		t.Fatalf("S3.ListAllObjects() error = %v", err)
+	}
+	if len(objects) != 2 || objects[1].Key != "xyz/b.txt" || objects[1].Size != 2 {
+		t.Errorf("S3.ListAllObjects() got = %+v", objects)
+	}
+}
+
+func TestS3_StreamListObjects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("continuation-token") {
+		case "":
+			io.WriteString(w, `<ListBucketResult><Contents><Key>a.txt</Key></Contents><Contents><Key>b.txt</Key></Contents>`+
+				`<IsTruncated>true</IsTruncated><NextContinuationToken>next</NextContinuationToken></ListBucketResult>`)
+		case "next":
+			io.WriteString(w, `<ListBucketResult><Contents><Key>c.txt</Key></Contents><IsTruncated>false</IsTruncated></ListBucketResult>`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	objects, errs := s3.StreamListObjects(context.Background(), ListInput{Bucket: "bucket"})
+	var keys []string
+	for obj := range objects {
+		keys = append(keys, obj.Key)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("S3.StreamListObjects() error = %v", err)
+	}
+	if want := []string{"a.txt", "b.txt", "c.txt"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("S3.StreamListObjects() got keys %v, want %v", keys, want)
+	}
+
+	objects, errs = s3.StreamListObjects(context.Background(), ListInput{Bucket: "bucket", ContinuationToken: "bad"})
+	for range objects {
+		t.Errorf("S3.StreamListObjects() sent an object for a failing listing")
+	}
+	var s3Err *S3Error
+	if err := <-errs; !errors.As(err, &s3Err) {
+		t.Errorf("S3.StreamListObjects() error = %v, want an S3Error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	objects, errs = s3.StreamListObjects(ctx, ListInput{Bucket: "bucket"})
+	<-objects
+	cancel()
+	for range objects {
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("S3.StreamListObjects() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestS3_StreamListObjects_CancelInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	objects, errs := s3.StreamListObjects(ctx, ListInput{Bucket: "bucket"})
+	<-started
+	cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		for range objects {
+		}
+		done <- <-errs
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("S3.StreamListObjects() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("S3.StreamListObjects() did not return after ctx was canceled")
+	}
+}
+
+func TestS3_BucketUsage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("prefix") != "logs/" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if q.Get("continuation-token") == "" {
+			io.WriteString(w, `<ListBucketResult><Contents><Key>logs/a</Key><Size>100</Size></Contents>`+
+				`<Contents><Key>logs/b</Key><Size>20</Size></Contents>`+
+				`<IsTruncated>true</IsTruncated><NextContinuationToken>next</NextContinuationToken></ListBucketResult>`)
+			return
+		}
+		io.WriteString(w, `<ListBucketResult><Contents><Key>logs/c</Key><Size>3</Size></Contents><IsTruncated>false</IsTruncated></ListBucketResult>`)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	var progress [][2]int64
+	totalBytes, objectCount, err := s3.BucketUsageWithProgress("bucket", "logs/", func(totalBytes, objectCount int64) {
+		progress = append(progress, [2]int64{totalBytes, objectCount})
+	})
+	if err != nil {
+		t.Fatalf("S3.BucketUsageWithProgress() error = %v", err)
+	}
+	if totalBytes != 123 || objectCount != 3 {
+		t.Errorf("S3.BucketUsageWithProgress() = %d bytes, %d objects, want 123 bytes, 3 objects", totalBytes, objectCount)
+	}
+	if want := [][2]int64{{120, 2}, {123, 3}}; !reflect.DeepEqual(progress, want) {
+		t.Errorf("S3.BucketUsageWithProgress() reported progress %v, want %v", progress, want)
+	}
+
+	if totalBytes, objectCount, err := s3.BucketUsage("bucket", "logs/"); err != nil || totalBytes != 123 || objectCount != 3 {
+		t.Errorf("S3.BucketUsage() = %d, %d, %v", totalBytes, objectCount, err)
+	}
+}
@@ -0,0 +1,105 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+)
+
+// Logger receives every request sent to S3, including retries,
+// and every response received, for debugging.
+type Logger interface {
+	LogRequest(req *http.Request)
+	LogResponse(resp *http.Response)
+}
+
+// WarningLogger is a Logger which also receives warnings about
+// the configuration of the client, such as UseFIPS enabled in
+// a region without FIPS endpoints.
+type WarningLogger interface {
+	Logger
+	LogWarning(msg string)
+}
+
+// maxDumpBody is the size above which the bodies of requests
+// and responses are left out of the output of StdoutLogger.
+const maxDumpBody = 1 << 10
+
+// StdoutLogger returns a Logger which dumps the raw requests and
+// responses to stdout. Bodies are only included up to 1 KiB, so
+// that objects are not read into memory, and larger bodies or those
+// of unknown length are replaced by a note. It should only be used
+// for debugging: the output contains the session token of temporary
+// credentials.
+func StdoutLogger() Logger {
+	return dumpLogger{w: os.Stdout}
+}
+
+// dumpLogger writes the output of httputil to w.
+type dumpLogger struct {
+	w io.Writer
+}
+
+func (l dumpLogger) LogRequest(req *http.Request) {
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	length := req.ContentLength
+	if hasBody && length == 0 {
+		// A zero length means unknown for outgoing requests.
+		length = -1
+	}
+	dumpBody := hasBody && length > 0 && length <= maxDumpBody
+	dump, err := httputil.DumpRequestOut(req, dumpBody)
+	if err != nil {
+		fmt.Fprintf(l.w, "gos3: dumping request: %v\n", err)
+		return
+	}
+	fmt.Fprintf(l.w, "%s%s\n", dump, skippedBody(hasBody && !dumpBody, length))
+}
+
+func (l dumpLogger) LogWarning(msg string) {
+	fmt.Fprintf(l.w, "gos3: %s\n", msg)
+}
+
+func (l dumpLogger) LogResponse(resp *http.Response) {
+	hasBody := resp.Body != nil && resp.Body != http.NoBody && resp.ContentLength != 0
+	dumpBody := hasBody && resp.ContentLength > 0 && resp.ContentLength <= maxDumpBody
+	dump, err := httputil.DumpResponse(resp, dumpBody)
+	if err != nil {
+		fmt.Fprintf(l.w, "gos3: dumping response: %v\n", err)
+		return
+	}
+	fmt.Fprintf(l.w, "%s%s\n", dump, skippedBody(hasBody && !dumpBody, resp.ContentLength))
+}
+
+// skippedBody returns the note replacing a body
+// left out of a dump, if skipped.
+func skippedBody(skipped bool, length int64) string {
+	switch {
+	case !skipped:
+		return ""
+	case length < 0:
+		return "[body of unknown length not dumped]"
+	default:
+		return fmt.Sprintf("[body of %d bytes not dumped]", length)
+	}
+}
+
+// SetLogger sets a Logger which is called with every
+// request and response. Pass nil to disable logging.
+func (s3 *S3) SetLogger(l Logger) *S3 {
+	s3.logger = l
+	return s3
+}
+
+// warnf sends a warning to the Logger of the
+// client, if it implements WarningLogger.
+func (s3 *S3) warnf(format string, args ...interface{}) {
+	if l, ok := s3.logger.(WarningLogger); ok {
+		l.LogWarning(fmt.Sprintf(format, args...))
+	}
+}
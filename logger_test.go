@@ -0,0 +1,81 @@
+package gos3
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestS3_SetLogger(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello world"))
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+	s3.SetLogger(dumpLogger{w: &buf})
+
+	body, err := s3.FileDownload(DownloadInput{Bucket: "bucket", ObjectKey: "test.txt"})
+	if err != nil {
+		t.Fatalf("S3.FileDownload() error = %v", err)
+	}
+	data, _ := ioutil.ReadAll(body)
+	body.Close()
+
+	// The body must still be readable after being dumped.
+	if string(data) != "hello world" {
+		t.Errorf("S3.FileDownload() = %s", data)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "GET /bucket/test.txt HTTP/1.1") || !strings.Contains(out, "Authorization: AWS4-HMAC-SHA256") {
+		t.Errorf("request not logged:\n%s", out)
+	}
+	if !strings.Contains(out, "HTTP/1.1 200 OK") || !strings.Contains(out, "\r\n\r\nhello world") {
+		t.Errorf("response not logged:\n%s", out)
+	}
+}
+
+func TestS3_SetLogger_LargeBody(t *testing.T) {
+	large := strings.Repeat("x", maxDumpBody+1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Write([]byte(large))
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+	s3.SetLogger(dumpLogger{w: &buf})
+
+	if _, err := s3.FilePut(UploadInput{
+		Bucket:    "bucket",
+		ObjectKey: "test.txt",
+		Body:      strings.NewReader(large),
+	}); err != nil {
+		t.Fatalf("S3.FilePut() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), fmt.Sprintf("[body of %d bytes not dumped]", len(large))) || strings.Contains(buf.String(), large) {
+		t.Errorf("large request body dumped:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	body, err := s3.FileDownload(DownloadInput{Bucket: "bucket", ObjectKey: "test.txt"})
+	if err != nil {
+		t.Fatalf("S3.FileDownload() error = %v", err)
+	}
+	data, _ := ioutil.ReadAll(body)
+	body.Close()
+	if string(data) != large {
+		t.Errorf("S3.FileDownload() read %d bytes, want %d", len(data), len(large))
+	}
+	if !strings.Contains(buf.String(), fmt.Sprintf("[body of %d bytes not dumped]", len(large))) || strings.Contains(buf.String(), large) {
+		t.Errorf("large response body dumped:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,32 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import "net/http"
+
+// RequestMetadata identifies a request in the logs of S3.
+// AWS support asks for both IDs when filing a case.
+type RequestMetadata struct {
+	// RequestID is the x-amz-request-id header of the response.
+	RequestID string
+	// HostID is the x-amz-id-2 header of the response.
+	HostID string
+}
+
+func newRequestMetadata(header http.Header) RequestMetadata {
+	return RequestMetadata{
+		RequestID: header.Get("x-amz-request-id"),
+		HostID:    header.Get("x-amz-id-2"),
+	}
+}
+
+// SetRequestMetadataFunc sets fn to be called with the RequestMetadata
+// of every response received, successful or not, including retries,
+// along with the request it answers, eg. to log the IDs of failed
+// requests. Pass nil to disable it. Failed requests also carry the IDs
+// in the RequestID and HostID of their S3Error.
+func (s3 *S3) SetRequestMetadataFunc(fn func(req *http.Request, md RequestMetadata)) *S3 {
+	s3.metadataFunc = fn
+	return s3
+}
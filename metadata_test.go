@@ -0,0 +1,46 @@
+package gos3
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestS3_SetRequestMetadataFunc(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-amz-request-id", "request-"+r.Method)
+		w.Header().Set("x-amz-id-2", "host-"+r.Method)
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	var got []RequestMetadata
+	s3.SetRequestMetadataFunc(func(req *http.Request, md RequestMetadata) {
+		got = append(got, md)
+	})
+
+	_, err := s3.FilePut(UploadInput{Bucket: "bucket", ObjectKey: "test.txt", Body: strings.NewReader("hello world")})
+	if err != nil {
+		t.Fatalf("S3.FilePut() error = %v", err)
+	}
+	err = s3.FileDelete(DeleteInput{Bucket: "bucket", ObjectKey: "test.txt"})
+	var s3Err *S3Error
+	if !errors.As(err, &s3Err) || s3Err.RequestID != "request-DELETE" || s3Err.HostID != "host-DELETE" {
+		t.Errorf("S3.FileDelete() error = %+v", err)
+	}
+
+	want := []RequestMetadata{
+		{RequestID: "request-PUT", HostID: "host-PUT"},
+		{RequestID: "request-DELETE", HostID: "host-DELETE"},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("RequestMetadata = %+v, want %+v", got, want)
+	}
+}
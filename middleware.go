@@ -0,0 +1,84 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Middleware wraps the http.RoundTripper sending the requests to S3,
+// eg. to log them, collect metrics, add headers or break circuits.
+// Requests reach the middleware signed, and are sent again through
+// it when retried.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// roundTripperFunc adapts a function to an http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Use adds middlewares wrapping the Transport of the http client.
+// They run in the order they are added, the first one receiving the
+// request first. The client set with SetClient is not modified,
+// so it can be shared with other code. The middlewares are applied
+// once, and again after every call to Use or SetClient.
+func (s3 *S3) Use(middlewares ...Middleware) *S3 {
+	s3.wrappedMu.Lock()
+	defer s3.wrappedMu.Unlock()
+
+	s3.middlewares = append(s3.middlewares, middlewares...)
+	s3.wrapped = nil
+	return s3
+}
+
+// wrapClient returns a copy of client whose
+// Transport is wrapped in the middlewares.
+func wrapClient(client *http.Client, middlewares []Middleware) *http.Client {
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		transport = middlewares[i](transport)
+	}
+
+	wrapped := *client
+	wrapped.Transport = transport
+	return &wrapped
+}
+
+// LoggingMiddleware returns a Middleware passing every
+// request and response to logger, like SetLogger.
+func LoggingMiddleware(logger Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			logger.LogRequest(req)
+			res, err := next.RoundTrip(req)
+			if res != nil {
+				logger.LogResponse(res)
+			}
+			return res, err
+		})
+	}
+}
+
+// MetricsMiddleware returns a Middleware calling counter with the
+// method of every request and the status code of its response,
+// eg. "404", or "error" if no response was received.
+func MetricsMiddleware(counter func(method, status string)) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			res, err := next.RoundTrip(req)
+			status := "error"
+			if err == nil {
+				status = strconv.Itoa(res.StatusCode)
+			}
+			counter(req.Method, status)
+			return res, err
+		})
+	}
+}
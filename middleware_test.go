@@ -0,0 +1,122 @@
+package gos3
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestS3_Use(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace") != "first,second" {
+			t.Errorf("X-Trace = %q, want first,second", r.Header.Get("X-Trace"))
+		}
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	trace := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				value := name
+				if v := req.Header.Get("X-Trace"); v != "" {
+					value = v + "," + name
+				}
+				req.Header.Set("X-Trace", value)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	var (
+		logs    bytes.Buffer
+		metrics []string
+	)
+	client := &http.Client{}
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+	s3.SetClient(client)
+	s3.Use(trace("first"), LoggingMiddleware(dumpLogger{w: &logs}))
+	s3.Use(trace("second"), MetricsMiddleware(func(method, status string) {
+		metrics = append(metrics, method+" "+status)
+	}))
+
+	if _, err := s3.FilePut(UploadInput{Bucket: "bucket", ObjectKey: "test.txt", Body: strings.NewReader("hello world")}); err != nil {
+		t.Fatalf("S3.FilePut() error = %v", err)
+	}
+	if err := s3.FileDelete(DeleteInput{Bucket: "bucket", ObjectKey: "test.txt"}); err != nil {
+		t.Fatalf("S3.FileDelete() error = %v", err)
+	}
+
+	if strings.Join(metrics, ";") != "PUT 200;DELETE 204" {
+		t.Errorf("MetricsMiddleware() counted %v", metrics)
+	}
+	if !strings.Contains(logs.String(), "PUT /bucket/test.txt") || !strings.Contains(logs.String(), "204 No Content") {
+		t.Errorf("LoggingMiddleware() logged %s", logs.String())
+	}
+	if client.Transport != nil {
+		t.Errorf("S3.Use() modified the client set with SetClient")
+	}
+}
+
+func TestS3_Use_Once(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	var built int
+	counting := func(next http.RoundTripper) http.RoundTripper {
+		built++
+		return next
+	}
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+	s3.Use(counting)
+	for i := 0; i < 3; i++ {
+		if _, err := s3.FileHead(DownloadInput{Bucket: "bucket", ObjectKey: "test.txt"}); err != nil {
+			t.Fatalf("S3.FileHead() error = %v", err)
+		}
+	}
+	if built != 1 {
+		t.Errorf("middleware applied %d times, want 1", built)
+	}
+
+	s3.SetClient(&http.Client{})
+	if _, err := s3.FileHead(DownloadInput{Bucket: "bucket", ObjectKey: "test.txt"}); err != nil {
+		t.Fatalf("S3.FileHead() error = %v", err)
+	}
+	if built != 2 {
+		t.Errorf("middleware applied %d times after SetClient, want 2", built)
+	}
+}
+
+func TestS3_Use_Concurrent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	noop := func(next http.RoundTripper) http.RoundTripper { return next }
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			s3.Use(noop)
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := s3.FileHead(DownloadInput{Bucket: "bucket", ObjectKey: "test.txt"}); err != nil {
+				t.Errorf("S3.FileHead() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
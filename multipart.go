@@ -0,0 +1,593 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// minPartSize is the smallest part size accepted by S3,
+	// except for the last part of an upload.
+	minPartSize = 5 * 1024 * 1024
+	// maxParts is the maximum number of parts in an upload.
+	maxParts = 10000
+)
+
+// MultipartUploadInput is passed to MultipartUpload as a parameter.
+type MultipartUploadInput struct {
+	UploadInput
+
+	// PartSize is the size of every part except the last one.
+	// Defaults to 5 MB, which is also the minimum allowed by S3.
+	PartSize int64
+
+	// Concurrency is the number of parts uploaded at once, defaults
+	// to 1. Up to Concurrency+1 parts are held in memory.
+	Concurrency int
+
+	// MaxPartRetries is the number of times a part failing with a
+	// transient error, such as a network error or a 500 or 503
+	// response, is sent again before the upload is aborted.
+	MaxPartRetries int
+}
+
+// CompletedPart is a part that has been uploaded
+// as part of a multipart upload.
+type CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+
+	// The checksum of the part, for uploads with a Checksum.
+	ChecksumCRC32  string `xml:"ChecksumCRC32,omitempty"`
+	ChecksumCRC32C string `xml:"ChecksumCRC32C,omitempty"`
+	ChecksumSHA1   string `xml:"ChecksumSHA1,omitempty"`
+	ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
+}
+
+// setChecksum sets the checksum of the part computed with algorithm,
+// which S3 expects back when the upload is completed.
+func (p *CompletedPart) setChecksum(algorithm, sum string) {
+	switch algorithm {
+	case ChecksumCRC32:
+		p.ChecksumCRC32 = sum
+	case ChecksumCRC32C:
+		p.ChecksumCRC32C = sum
+	case ChecksumSHA1:
+		p.ChecksumSHA1 = sum
+	case ChecksumSHA256:
+		p.ChecksumSHA256 = sum
+	}
+}
+
+// initiateMultipartUploadResult is returned by S3
+// when a multipart upload is created.
+type initiateMultipartUploadResult struct {
+	Bucket   string `xml:"Bucket"`
+	Key      string `xml:"Key"`
+	UploadID string `xml:"UploadId"`
+}
+
+// completeMultipartUpload is sent to S3 to assemble
+// the uploaded parts into the final object.
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []CompletedPart `xml:"Part"`
+}
+
+// completeMultipartUploadResult receives the
+// CompleteMultipartUploadResult XML in case of success.
+// S3 may also send a 200 OK with an <Error> document,
+// which is detected through XMLName.
+type completeMultipartUploadResult struct {
+	XMLName  xml.Name
+	Location string `xml:"Location"`
+	Bucket   string `xml:"Bucket"`
+	Key      string `xml:"Key"`
+	ETag     string `xml:"ETag"`
+}
+
+// MultipartUpload uploads the body in parts of u.PartSize bytes using
+// the S3 multipart upload API, up to u.Concurrency parts at once. It is
+// better suited than FileUpload or FilePut for large files. If any part
+// fails, once retried u.MaxPartRetries times, the upload is aborted
+// so that S3 does not keep the uploaded parts around.
+func (s3 *S3) MultipartUpload(u MultipartUploadInput) (UploadResponse, error) {
+	partSize, err := u.validatedPartSize()
+	if err != nil {
+		return UploadResponse{}, err
+	}
+	return s3.uploadParts(u.UploadInput, u.Body, partSize, u.Concurrency, u.MaxPartRetries)
+}
+
+// validatedPartSize validates the input and returns the
+// size of its parts, checking that the body does not
+// need more than the maximum number of parts.
+func (u MultipartUploadInput) validatedPartSize() (int64, error) {
+	if err := u.validate(); err != nil {
+		return 0, err
+	}
+
+	partSize := u.PartSize
+	if partSize == 0 {
+		partSize = minPartSize
+	}
+	if partSize < minPartSize {
+		return 0, fmt.Errorf("part size %d is smaller than the minimum of %d bytes", partSize, minPartSize)
+	}
+
+	fSize, err := detectFileSize(u.Body)
+	if err != nil {
+		return 0, err
+	}
+	numParts := (fSize + partSize - 1) / partSize
+	if numParts == 0 {
+		// S3 needs at least one part, even if it is empty.
+		numParts = 1
+	}
+	if numParts > maxParts {
+		return 0, fmt.Errorf("file of %d bytes needs %d parts, more than the maximum of %d", fSize, numParts, maxParts)
+	}
+	return partSize, nil
+}
+
+// uploadParts reads r until EOF and uploads it as a multipart upload
+// with parts of partSize bytes, up to concurrency parts at once, each
+// retried up to maxRetries times. If any part fails, the upload is
+// aborted so that S3 does not keep the uploaded parts around.
+func (s3 *S3) uploadParts(u UploadInput, r io.Reader, partSize int64, concurrency, maxRetries int) (UploadResponse, error) {
+	uploadID, err := s3.initiateMultipartUpload(u)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+
+	parts, err := partUploader{
+		s3:          s3,
+		u:           u,
+		uploadID:    uploadID,
+		partSize:    partSize,
+		concurrency: concurrency,
+		maxRetries:  maxRetries,
+	}.upload(r)
+	if err != nil {
+		s3.AbortMultipartUpload(u.Bucket, u.ObjectKey, uploadID)
+		return UploadResponse{}, err
+	}
+
+	resp, err := s3.completeMultipartUpload(u.Bucket, u.ObjectKey, uploadID, parts)
+	if err != nil {
+		s3.AbortMultipartUpload(u.Bucket, u.ObjectKey, uploadID)
+		return UploadResponse{}, err
+	}
+	return resp, nil
+}
+
+// partUploader uploads the parts of the multipart upload uploadID.
+type partUploader struct {
+	s3          *S3
+	u           UploadInput
+	uploadID    string
+	partSize    int64
+	concurrency int
+	maxRetries  int
+
+	// uploaded are the ETags of the parts uploaded
+	// beforehand, which are read but not sent again.
+	uploaded map[int]string
+	// onPart, if set, is called once a part is uploaded.
+	onPart func(CompletedPart)
+}
+
+// upload reads r until EOF and uploads it in parts of p.partSize bytes,
+// up to p.concurrency parts at once, each retried up to p.maxRetries
+// times. It stops at the first part which fails, and otherwise
+// returns all the parts of the upload in order.
+func (p partUploader) upload(r io.Reader) ([]CompletedPart, error) {
+	concurrency := p.concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	type job struct {
+		partNumber int
+		data       []byte
+	}
+	var (
+		jobs     = make(chan job)
+		failed   = make(chan struct{})
+		once     sync.Once
+		firstErr error
+
+		mu    sync.Mutex
+		parts []CompletedPart
+		wg    sync.WaitGroup
+	)
+	// fail records the first error and
+	// stops the reading of further parts.
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			close(failed)
+		})
+	}
+	done := func(part CompletedPart) {
+		mu.Lock()
+		parts = append(parts, part)
+		mu.Unlock()
+	}
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				checksum := p.u.partChecksum(j.data)
+				etag, err := p.s3.uploadPartWithRetries(p.u, p.uploadID, j.partNumber, j.data, checksum, p.maxRetries)
+				if err != nil {
+					fail(err)
+					continue
+				}
+				part := CompletedPart{PartNumber: j.partNumber, ETag: etag}
+				part.setChecksum(p.u.Checksum, checksum)
+				done(part)
+				if p.onPart != nil {
+					p.onPart(part)
+				}
+			}
+		}()
+	}
+
+	readErr := func() error {
+		for partNumber := 1; ; partNumber++ {
+			// Parts are uploaded while the next ones are
+			// read, each needs a buffer of its own.
+			buf := make([]byte, p.partSize)
+			n, rerr := io.ReadFull(r, buf)
+			if rerr == io.EOF && partNumber > 1 {
+				return nil
+			}
+			if rerr != nil && rerr != io.EOF && rerr != io.ErrUnexpectedEOF {
+				return rerr
+			}
+			if partNumber > maxParts {
+				return fmt.Errorf("upload needs more than the maximum of %d parts", maxParts)
+			}
+
+			if etag, ok := p.uploaded[partNumber]; ok {
+				part := CompletedPart{PartNumber: partNumber, ETag: etag}
+				part.setChecksum(p.u.Checksum, p.u.partChecksum(buf[:n]))
+				done(part)
+			} else {
+				select {
+				case jobs <- job{partNumber: partNumber, data: buf[:n]}:
+				case <-failed:
+					return nil
+				}
+			}
+
+			// A short read means this was the last part.
+			if rerr != nil {
+				return nil
+			}
+		}
+	}()
+	close(jobs)
+	wg.Wait()
+
+	if readErr != nil {
+		fail(readErr)
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+	return parts, nil
+}
+
+// uploadPartWithRetries is like uploadPart, but sends the part
+// again up to maxRetries times if it fails with a transient error,
+// waiting as the default RetryPolicy does in between.
+func (s3 *S3) uploadPartWithRetries(u UploadInput, uploadID string, partNumber int, part []byte, checksum string, maxRetries int) (string, error) {
+	for attempt := 1; ; attempt++ {
+		etag, err := s3.uploadPart(u, uploadID, partNumber, part, checksum)
+		if err == nil || attempt > maxRetries || !isTransient(err) {
+			return etag, err
+		}
+		time.Sleep(defaultRetryPolicy{}.Delay(attempt))
+	}
+}
+
+// isTransient reports whether a request which failed with err
+// may succeed if sent again, as decided by the default RetryPolicy.
+func isTransient(err error) bool {
+	p := defaultRetryPolicy{maxAttempts: 2}
+	var s3Err *S3Error
+	if errors.As(err, &s3Err) {
+		return p.ShouldRetry(1, nil, s3Err.StatusCode)
+	}
+	return p.ShouldRetry(1, err, 0)
+}
+
+// AbortMultipartUpload makes a DELETE call to abort the multipart
+// upload identified by uploadID, freeing the parts uploaded so far.
+func (s3 *S3) AbortMultipartUpload(bucket, key, uploadID string) error {
+	req, err := s3.newRequest(
+		context.Background(), http.MethodDelete, bucket, key, "?uploadId="+url.QueryEscape(uploadID), nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := s3.signRequest(req); err != nil {
+		return err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 204 {
+		return newResponseError(res)
+	}
+	return nil
+}
+
+// InitiateMultipartUpload makes a POST call to create a multipart
+// upload and returns its upload ID. It is only needed to drive the
+// upload from elsewhere, eg. a browser using the URLs created by
+// PresignUploadPartURL, as MultipartUpload initiates its own uploads.
+func (s3 *S3) InitiateMultipartUpload(u UploadInput) (string, error) {
+	if err := u.validate(); err != nil {
+		return "", err
+	}
+	return s3.initiateMultipartUpload(u)
+}
+
+// initiateMultipartUpload makes a POST call to create a multipart
+// upload and returns the upload ID used by the following calls.
+func (s3 *S3) initiateMultipartUpload(u UploadInput) (string, error) {
+	req, err := s3.newRequest(
+		context.Background(), http.MethodPost, u.Bucket, u.ObjectKey, "?uploads", nil,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	u.setHeaders(req.Header)
+	if u.Checksum != "" {
+		req.Header.Set("x-amz-checksum-algorithm", u.Checksum)
+	}
+
+	if err := s3.signRequest(req); err != nil {
+		return "", err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return "", newResponseError(res)
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var result initiateMultipartUploadResult
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return "", err
+	}
+	if result.UploadID == "" {
+		return "", errors.New("no upload id in response")
+	}
+	return result.UploadID, nil
+}
+
+// uploadPart makes a PUT call with a single part of u and returns
+// the ETag of the part. The SSE-C key of the upload, if any, must
+// be sent again with every part, as must the checksum of the part
+// if the upload has a Checksum.
+func (s3 *S3) uploadPart(u UploadInput, uploadID string, partNumber int, part []byte, checksum string) (string, error) {
+	req, err := s3.newRequest(
+		context.Background(), http.MethodPut, u.Bucket, u.ObjectKey,
+		"?partNumber="+strconv.Itoa(partNumber)+"&uploadId="+url.QueryEscape(uploadID),
+		bytes.NewReader(part),
+	)
+	if err != nil {
+		return "", err
+	}
+	setSSECHeaders(req.Header, u.SSECKey)
+	if checksum != "" {
+		req.Header.Set(checksumHeader(u.Checksum), checksum)
+	}
+
+	if err := s3.signRequestWithBody(req, part); err != nil {
+		return "", err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return "", fmt.Errorf("part %d: %w", partNumber, newResponseError(res))
+	}
+	return res.Header.Get("ETag"), nil
+}
+
+// completeMultipartUpload makes a POST call listing
+// the uploaded parts to assemble the final object.
+func (s3 *S3) completeMultipartUpload(bucket, key, uploadID string, parts []CompletedPart) (UploadResponse, error) {
+	body, err := xml.Marshal(completeMultipartUpload{Parts: parts})
+	if err != nil {
+		return UploadResponse{}, err
+	}
+
+	req, err := s3.newRequest(
+		context.Background(), http.MethodPost, bucket, key, "?uploadId="+url.QueryEscape(uploadID), bytes.NewReader(body),
+	)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/xml")
+
+	if err := s3.signRequestWithBody(req, body); err != nil {
+		return UploadResponse{}, err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return UploadResponse{}, newResponseError(res)
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+
+	var result completeMultipartUploadResult
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return UploadResponse{}, err
+	}
+	if result.XMLName.Local == "Error" {
+		return UploadResponse{}, parseS3Error(res.StatusCode, res.Header, data)
+	}
+
+	return UploadResponse{
+		Location: result.Location,
+		Bucket:   result.Bucket,
+		Key:      result.Key,
+		ETag:     result.ETag,
+	}, nil
+}
+
+// IncompleteUpload is a multipart upload which was
+// neither completed nor aborted.
+type IncompleteUpload struct {
+	Key          string    `xml:"Key"`
+	UploadID     string    `xml:"UploadId"`
+	Initiated    time.Time `xml:"Initiated"`
+	StorageClass string    `xml:"StorageClass"`
+}
+
+// listMultipartUploadsResult receives the
+// ListMultipartUploadsResult XML.
+type listMultipartUploadsResult struct {
+	Uploads            []IncompleteUpload `xml:"Upload"`
+	IsTruncated        bool               `xml:"IsTruncated"`
+	NextKeyMarker      string             `xml:"NextKeyMarker"`
+	NextUploadIDMarker string             `xml:"NextUploadIdMarker"`
+}
+
+// ListIncompleteMultiparts makes GET calls to list the multipart
+// uploads in progress for the keys starting with prefix. Their
+// parts are billed until the uploads are completed or aborted.
+func (s3 *S3) ListIncompleteMultiparts(bucket, prefix string) ([]IncompleteUpload, error) {
+	var uploads []IncompleteUpload
+	query := url.Values{}
+	query.Set("uploads", "")
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	for {
+		var result listMultipartUploadsResult
+		if err := s3.doXML(http.MethodGet, bucket, "", "?"+query.Encode(), nil, nil, &result); err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, result.Uploads...)
+
+		if !result.IsTruncated || result.NextKeyMarker == "" {
+			return uploads, nil
+		}
+		query.Set("key-marker", result.NextKeyMarker)
+		query.Set("upload-id-marker", result.NextUploadIDMarker)
+	}
+}
+
+// UploadedPart is a part of a multipart upload in progress.
+type UploadedPart struct {
+	PartNumber   int       `xml:"PartNumber"`
+	ETag         string    `xml:"ETag"`
+	Size         int64     `xml:"Size"`
+	LastModified time.Time `xml:"LastModified"`
+}
+
+// listPartsResult receives the ListPartsResult XML.
+type listPartsResult struct {
+	Parts                []UploadedPart `xml:"Part"`
+	IsTruncated          bool           `xml:"IsTruncated"`
+	NextPartNumberMarker string         `xml:"NextPartNumberMarker"`
+}
+
+// ListParts makes GET calls to list the parts uploaded
+// so far to the multipart upload uploadID.
+func (s3 *S3) ListParts(bucket, key, uploadID string) ([]UploadedPart, error) {
+	var parts []UploadedPart
+	query := url.Values{}
+	query.Set("uploadId", uploadID)
+	for {
+		var result listPartsResult
+		if err := s3.doXML(http.MethodGet, bucket, key, "?"+query.Encode(), nil, nil, &result); err != nil {
+			return nil, err
+		}
+		parts = append(parts, result.Parts...)
+
+		if !result.IsTruncated || result.NextPartNumberMarker == "" {
+			return parts, nil
+		}
+		query.Set("part-number-marker", result.NextPartNumberMarker)
+	}
+}
+
+// AbortAllIncompleteMultiparts aborts the multipart uploads for the
+// keys starting with prefix which were initiated more than olderThan
+// ago, and returns the number of aborted uploads. It stops at the
+// first upload which cannot be aborted.
+func (s3 *S3) AbortAllIncompleteMultiparts(bucket, prefix string, olderThan time.Duration) (int, error) {
+	uploads, err := s3.ListIncompleteMultiparts(bucket, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	aborted := 0
+	cutoff := nowTime().Add(-olderThan)
+	for _, u := range uploads {
+		if !u.Initiated.Before(cutoff) {
+			continue
+		}
+		if err := s3.AbortMultipartUpload(bucket, u.Key, u.UploadID); err != nil {
+			return aborted, fmt.Errorf("%s: %w", u.Key, err)
+		}
+		aborted++
+	}
+	return aborted, nil
+}
@@ -0,0 +1,213 @@
+package gos3
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// multipartServer is a minimal in-memory implementation
+// of the S3 multipart upload API.
+type multipartServer struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	parts    map[string][]byte
+	aborted  bool
+	complete []byte
+}
+
+func (m *multipartServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q := r.URL.Query()
+	_, uploads := q["uploads"]
+	switch {
+	case r.Method == http.MethodPost && uploads:
+		io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>key</Key><UploadId>upload-id</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == http.MethodPut && q.Get("uploadId") == "upload-id":
+		data, _ := ioutil.ReadAll(r.Body)
+		m.parts[q.Get("partNumber")] = data
+		w.Header().Set("ETag", `"etag-`+q.Get("partNumber")+`"`)
+	case r.Method == http.MethodPost && q.Get("uploadId") == "upload-id":
+		var c completeMultipartUpload
+		data, _ := ioutil.ReadAll(r.Body)
+		if err := xml.Unmarshal(data, &c); err != nil {
+			m.t.Errorf("invalid complete body: %v", err)
+		}
+		for _, p := range c.Parts {
+			if want := fmt.Sprintf(`"etag-%d"`, p.PartNumber); p.ETag != want {
+				m.t.Errorf("part %d: got etag %s, want %s", p.PartNumber, p.ETag, want)
+			}
+			m.complete = append(m.complete, m.parts[fmt.Sprint(p.PartNumber)]...)
+		}
+		io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>key</Key><ETag>"final"</ETag></CompleteMultipartUploadResult>`)
+	case r.Method == http.MethodGet && q.Get("uploadId") == "upload-id":
+		io.WriteString(w, `<ListPartsResult><IsTruncated>false</IsTruncated>`)
+		for i := 1; i <= maxParts; i++ {
+			if data, ok := m.parts[fmt.Sprint(i)]; ok {
+				fmt.Fprintf(w, `<Part><PartNumber>%d</PartNumber><ETag>"etag-%d"</ETag><Size>%d</Size></Part>`, i, i, len(data))
+			}
+		}
+		io.WriteString(w, `</ListPartsResult>`)
+	case r.Method == http.MethodDelete && q.Get("uploadId") == "upload-id":
+		m.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		m.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestS3_MultipartUpload(t *testing.T) {
+	m := &multipartServer{t: t, parts: map[string][]byte{}}
+	ts := httptest.NewServer(m)
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	data := bytes.Repeat([]byte("0123456789"), minPartSize/10+1)
+	resp, err := s3.MultipartUpload(MultipartUploadInput{
+		UploadInput: UploadInput{
+			Bucket:      "bucket",
+			ObjectKey:   "key",
+			ContentType: "application/octet-stream",
+			Body:        bytes.NewReader(data),
+		},
+	})
+	if err != nil {
+		t.Fatalf("S3.MultipartUpload() error = %v", err)
+	}
+	if resp.ETag != `"final"` {
+		t.Errorf("S3.MultipartUpload() got = %v", resp)
+	}
+	if len(m.parts) != 2 {
+		t.Errorf("S3.MultipartUpload() uploaded %d parts, want 2", len(m.parts))
+	}
+	if !bytes.Equal(m.complete, data) {
+		t.Errorf("S3.MultipartUpload() assembled object does not match the input")
+	}
+}
+
+func TestS3_MultipartUpload_Concurrency(t *testing.T) {
+	m := &multipartServer{t: t, parts: map[string][]byte{}}
+	var (
+		mu       sync.Mutex
+		failures = map[string]int{}
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Part 2 fails once, part 3 always fails.
+		if part := r.URL.Query().Get("partNumber"); r.Method == http.MethodPut && (part == "2" || part == "3") {
+			mu.Lock()
+			failures[part]++
+			n := failures[part]
+			mu.Unlock()
+			if part == "3" || n == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		m.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	data := bytes.Repeat([]byte("0123456789"), minPartSize/10*2+1)
+	input := MultipartUploadInput{
+		UploadInput: UploadInput{
+			Bucket:    "bucket",
+			ObjectKey: "key",
+			Body:      bytes.NewReader(data[:minPartSize*2]),
+		},
+		Concurrency:    3,
+		MaxPartRetries: 2,
+	}
+	if _, err := s3.MultipartUpload(input); err != nil {
+		t.Fatalf("S3.MultipartUpload() error = %v", err)
+	}
+	if !bytes.Equal(m.complete, data[:minPartSize*2]) || failures["2"] != 2 {
+		t.Errorf("S3.MultipartUpload() assembled %d bytes after %d attempts of part 2", len(m.complete), failures["2"])
+	}
+
+	m.complete = nil
+	input.Body = bytes.NewReader(data)
+	if _, err := s3.MultipartUpload(input); err == nil {
+		t.Errorf("S3.MultipartUpload() expected an error for a failing part")
+	}
+	if !m.aborted || m.complete != nil || failures["3"] != 3 {
+		t.Errorf("S3.MultipartUpload() aborted = %v after %d attempts of part 3", m.aborted, failures["3"])
+	}
+}
+
+func TestS3_MultipartUpload_PartSize(t *testing.T) {
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	_, err := s3.MultipartUpload(MultipartUploadInput{
+		UploadInput: UploadInput{
+			Bucket:    "bucket",
+			ObjectKey: "key",
+			Body:      bytes.NewReader(nil),
+		},
+		PartSize: 1024,
+	})
+	if err == nil {
+		t.Errorf("S3.MultipartUpload() expected an error for a part size below the minimum")
+	}
+}
+
+func TestS3_AbortAllIncompleteMultiparts(t *testing.T) {
+	now := time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)
+	defer func(f func() time.Time) { nowTime = f }(nowTime)
+	nowTime = func() time.Time { return now }
+
+	var aborted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodGet && q.Get("prefix") == "tmp/" && q.Get("key-marker") == "":
+			io.WriteString(w, `<ListMultipartUploadsResult>
+  <IsTruncated>true</IsTruncated><NextKeyMarker>tmp/b</NextKeyMarker><NextUploadIdMarker>id-b</NextUploadIdMarker>
+  <Upload><Key>tmp/a</Key><UploadId>id-a</UploadId><Initiated>2020-01-01T00:00:00.000Z</Initiated></Upload>
+  <Upload><Key>tmp/b</Key><UploadId>id-b</UploadId><Initiated>2020-01-09T12:00:00.000Z</Initiated></Upload>
+</ListMultipartUploadsResult>`)
+		case r.Method == http.MethodGet && q.Get("key-marker") == "tmp/b" && q.Get("upload-id-marker") == "id-b":
+			io.WriteString(w, `<ListMultipartUploadsResult>
+  <IsTruncated>false</IsTruncated>
+  <Upload><Key>tmp/c</Key><UploadId>id-c</UploadId><Initiated>2020-01-02T00:00:00.000Z</Initiated></Upload>
+</ListMultipartUploadsResult>`)
+		case r.Method == http.MethodDelete:
+			aborted = append(aborted, q.Get("uploadId"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	uploads, err := s3.ListIncompleteMultiparts("bucket", "tmp/")
+	if err != nil || len(uploads) != 3 {
+		t.Fatalf("S3.ListIncompleteMultiparts() = %v, %v", uploads, err)
+	}
+
+	n, err := s3.AbortAllIncompleteMultiparts("bucket", "tmp/", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("S3.AbortAllIncompleteMultiparts() error = %v", err)
+	}
+	if n != 2 || strings.Join(aborted, ",") != "id-a,id-c" {
+		t.Errorf("S3.AbortAllIncompleteMultiparts() = %d, aborted %v", n, aborted)
+	}
+}
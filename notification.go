@@ -0,0 +1,118 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// NotificationConfiguration lists the destinations notified
+// of the events happening to the objects of a bucket.
+type NotificationConfiguration struct {
+	LambdaFunctions     []LambdaFunctionConfiguration `xml:"CloudFunctionConfiguration"`
+	QueueConfigurations []QueueConfiguration          `xml:"QueueConfiguration"`
+	TopicConfigurations []TopicConfiguration          `xml:"TopicConfiguration"`
+}
+
+// LambdaFunctionConfiguration invokes a Lambda function on Events,
+// such as "s3:ObjectCreated:*".
+type LambdaFunctionConfiguration struct {
+	ID                string             `xml:"Id,omitempty"`
+	LambdaFunctionARN string             `xml:"CloudFunction"`
+	Events            []string           `xml:"Event"`
+	Filter            NotificationFilter `xml:"Filter"`
+}
+
+// QueueConfiguration sends a message to an SQS queue on Events.
+type QueueConfiguration struct {
+	ID       string             `xml:"Id,omitempty"`
+	QueueARN string             `xml:"Queue"`
+	Events   []string           `xml:"Event"`
+	Filter   NotificationFilter `xml:"Filter"`
+}
+
+// TopicConfiguration publishes a message to an SNS topic on Events.
+type TopicConfiguration struct {
+	ID       string             `xml:"Id,omitempty"`
+	TopicARN string             `xml:"Topic"`
+	Events   []string           `xml:"Event"`
+	Filter   NotificationFilter `xml:"Filter"`
+}
+
+// NotificationFilter restricts notifications to the objects whose key
+// starts with Prefix and ends with Suffix. Empty values match any key.
+type NotificationFilter struct {
+	Prefix string
+	Suffix string
+}
+
+// filterRule is the XML representation of a NotificationFilter.
+type filterRule struct {
+	Name  string `xml:"Name"`
+	Value string `xml:"Value"`
+}
+
+type s3KeyFilter struct {
+	FilterRules []filterRule `xml:"S3Key>FilterRule"`
+}
+
+// MarshalXML implements xml.Marshaler. Nothing is
+// written for a filter matching any key.
+func (f NotificationFilter) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	var filter s3KeyFilter
+	if f.Prefix != "" {
+		filter.FilterRules = append(filter.FilterRules, filterRule{Name: "prefix", Value: f.Prefix})
+	}
+	if f.Suffix != "" {
+		filter.FilterRules = append(filter.FilterRules, filterRule{Name: "suffix", Value: f.Suffix})
+	}
+	if len(filter.FilterRules) == 0 {
+		return nil
+	}
+	return e.EncodeElement(filter, start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler.
+func (f *NotificationFilter) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var filter s3KeyFilter
+	if err := d.DecodeElement(&filter, &start); err != nil {
+		return err
+	}
+	for _, rule := range filter.FilterRules {
+		switch rule.Name {
+		case "prefix", "Prefix":
+			f.Prefix = rule.Value
+		case "suffix", "Suffix":
+			f.Suffix = rule.Value
+		}
+	}
+	return nil
+}
+
+// notificationConfiguration adds the root element
+// to a NotificationConfiguration.
+type notificationConfiguration struct {
+	XMLName xml.Name `xml:"NotificationConfiguration"`
+	NotificationConfiguration
+}
+
+// PutBucketNotification makes a PUT call to replace the notification
+// configuration of a bucket with cfg. An empty cfg disables
+// all notifications.
+func (s3 *S3) PutBucketNotification(bucket string, cfg NotificationConfiguration) error {
+	return s3.doXML(http.MethodPut, bucket, "", "?notification", nil, notificationConfiguration{
+		NotificationConfiguration: cfg,
+	}, nil)
+}
+
+// GetBucketNotification makes a GET call and returns
+// the notification configuration of a bucket.
+func (s3 *S3) GetBucketNotification(bucket string) (NotificationConfiguration, error) {
+	var cfg notificationConfiguration
+	if err := s3.doXML(http.MethodGet, bucket, "", "?notification", nil, nil, &cfg); err != nil {
+		return NotificationConfiguration{}, err
+	}
+	return cfg.NotificationConfiguration, nil
+}
@@ -0,0 +1,58 @@
+package gos3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestS3_BucketNotification(t *testing.T) {
+	var stored []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["notification"]; !ok || r.URL.Path != "/bucket" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			stored, _ = ioutil.ReadAll(r.Body)
+		case http.MethodGet:
+			w.Write(stored)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	cfg := NotificationConfiguration{
+		LambdaFunctions: []LambdaFunctionConfiguration{{
+			ID:                "thumbnails",
+			LambdaFunctionARN: "arn:aws:lambda:us-east-1:123456789012:function:thumbnail",
+			Events:            []string{"s3:ObjectCreated:*"},
+			Filter:            NotificationFilter{Prefix: "images/", Suffix: ".jpg"},
+		}},
+		QueueConfigurations: []QueueConfiguration{{
+			QueueARN: "arn:aws:sqs:us-east-1:123456789012:deletions",
+			Events:   []string{"s3:ObjectRemoved:*"},
+		}},
+	}
+	if err := s3.PutBucketNotification("bucket", cfg); err != nil {
+		t.Fatalf("S3.PutBucketNotification() error = %v", err)
+	}
+	body := string(stored)
+	if !strings.Contains(body, "<FilterRule><Name>prefix</Name><Value>images/</Value></FilterRule>") ||
+		strings.Count(body, "<Filter>") != 1 {
+		t.Errorf("S3.PutBucketNotification() sent = %s", stored)
+	}
+
+	got, err := s3.GetBucketNotification("bucket")
+	if err != nil {
+		t.Fatalf("S3.GetBucketNotification() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("S3.GetBucketNotification() = %+v, want %+v", got, cfg)
+	}
+}
@@ -0,0 +1,43 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxObjectKeyLength is the maximum length
+// of an object key, in bytes.
+const maxObjectKeyLength = 1024
+
+// ValidateObjectKey checks that key can be used as is as the key of an
+// object. S3 accepts keys which are valid UTF-8 of up to 1024 bytes, but
+// null bytes are rejected as they lead to confusing keys. Leading and
+// repeated slashes are valid and kept as is; see NormalizeObjectKey to
+// strip them. The returned error matches ErrInvalidObjectKey.
+func ValidateObjectKey(key string) error {
+	switch {
+	case key == "":
+		return fmt.Errorf("%w: empty key", ErrInvalidObjectKey)
+	case len(key) > maxObjectKeyLength:
+		return fmt.Errorf("%w: key is %d bytes long, more than the maximum of %d", ErrInvalidObjectKey, len(key), maxObjectKeyLength)
+	case !utf8.ValidString(key):
+		return fmt.Errorf("%w: %q is not valid UTF-8", ErrInvalidObjectKey, key)
+	}
+	if i := strings.IndexByte(key, 0); i >= 0 {
+		return fmt.Errorf("%w: %q has a null byte at offset %d", ErrInvalidObjectKey, key, i)
+	}
+	return nil
+}
+
+// NormalizeObjectKey strips the leading slashes of key
+// and collapses its repeated slashes into one.
+func NormalizeObjectKey(key string) string {
+	for strings.Contains(key, "//") {
+		key = strings.Replace(key, "//", "/", -1)
+	}
+	return strings.TrimLeft(key, "/")
+}
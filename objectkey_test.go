@@ -0,0 +1,69 @@
+package gos3
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateObjectKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		wantErr bool
+	}{
+		{"test.txt", false},
+		{"dir/sub dir/ünïcode.txt", false},
+		{"dir/", false},
+		{strings.Repeat("k", maxObjectKeyLength), false},
+		{"", true},
+		{"/test.txt", false},
+		{"dir//test.txt", false},
+		{"test\x00.txt", true},
+		{"\xff.txt", true},
+		{strings.Repeat("k", maxObjectKeyLength+1), true},
+	}
+	for _, tt := range tests {
+		err := ValidateObjectKey(tt.key)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateObjectKey(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidObjectKey) {
+			t.Errorf("ValidateObjectKey(%q) error = %v, want ErrInvalidObjectKey", tt.key, err)
+		}
+	}
+}
+
+func TestNormalizeObjectKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"test.txt", "test.txt"},
+		{"/test.txt", "test.txt"},
+		{"//dir///sub//test.txt", "dir/sub/test.txt"},
+		{"dir/", "dir/"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeObjectKey(tt.key); got != tt.want {
+			t.Errorf("NormalizeObjectKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestS3_InvalidObjectKey(t *testing.T) {
+	// No endpoint is needed, the key is checked before any request.
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+
+	if _, err := s3.FilePut(UploadInput{Bucket: "bucket", ObjectKey: "\xfftest.txt", Body: strings.NewReader("")}); !errors.Is(err, ErrInvalidObjectKey) {
+		t.Errorf("S3.FilePut() error = %v, want ErrInvalidObjectKey", err)
+	}
+	if _, err := s3.FileDownload(DownloadInput{Bucket: "bucket", ObjectKey: "a\x00b"}); !errors.Is(err, ErrInvalidObjectKey) {
+		t.Errorf("S3.FileDownload() error = %v, want ErrInvalidObjectKey", err)
+	}
+	if _, err := s3.FileHead(DownloadInput{Bucket: "bucket", ObjectKey: "a\x00b"}); !errors.Is(err, ErrInvalidObjectKey) {
+		t.Errorf("S3.FileHead() error = %v, want ErrInvalidObjectKey", err)
+	}
+	if err := s3.FileDelete(DeleteInput{Bucket: "bucket", ObjectKey: ""}); !errors.Is(err, ErrInvalidObjectKey) {
+		t.Errorf("S3.FileDelete() error = %v, want ErrInvalidObjectKey", err)
+	}
+}
@@ -0,0 +1,86 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Retention modes of PutObjectRetention.
+const (
+	// RetentionModeGovernance can be lifted by users with
+	// the s3:BypassGovernanceRetention permission.
+	RetentionModeGovernance = "GOVERNANCE"
+	// RetentionModeCompliance cannot be lifted by anyone,
+	// including the root user, until it expires.
+	RetentionModeCompliance = "COMPLIANCE"
+)
+
+// ObjectLockOption configures the requests
+// of PutObjectRetention and PutObjectLegalHold.
+type ObjectLockOption func(http.Header)
+
+// BypassGovernanceRetention allows shortening or removing
+// a retention in GOVERNANCE mode.
+func BypassGovernanceRetention() ObjectLockOption {
+	return func(h http.Header) {
+		h.Set("x-amz-bypass-governance-retention", "true")
+	}
+}
+
+// retention is the XML representation of an object retention.
+type retention struct {
+	XMLName         xml.Name `xml:"Retention"`
+	Mode            string   `xml:"Mode"`
+	RetainUntilDate string   `xml:"RetainUntilDate"`
+}
+
+// legalHold is the XML representation of an object legal hold.
+type legalHold struct {
+	XMLName xml.Name `xml:"LegalHold"`
+	Status  string   `xml:"Status"`
+}
+
+// PutObjectRetention makes a PUT call to prevent a version of an object,
+// or the latest one if versionID is empty, from being deleted or
+// overwritten until retainUntil. The bucket must have object
+// lock enabled.
+func (s3 *S3) PutObjectRetention(bucket, key, versionID, mode string, retainUntil time.Time, opts ...ObjectLockOption) error {
+	return s3.doXML(http.MethodPut, bucket, key, objectLockQuery("retention", versionID), objectLockHeader(opts), retention{
+		Mode:            mode,
+		RetainUntilDate: retainUntil.UTC().Format(time.RFC3339),
+	}, nil)
+}
+
+// PutObjectLegalHold makes a PUT call to place or remove a legal hold,
+// which prevents a version of an object, or the latest one if versionID
+// is empty, from being deleted or overwritten until it is removed.
+func (s3 *S3) PutObjectLegalHold(bucket, key, versionID string, hold bool, opts ...ObjectLockOption) error {
+	status := "OFF"
+	if hold {
+		status = "ON"
+	}
+	return s3.doXML(http.MethodPut, bucket, key, objectLockQuery("legal-hold", versionID), objectLockHeader(opts), legalHold{
+		Status: status,
+	}, nil)
+}
+
+func objectLockQuery(subresource, versionID string) string {
+	query := "?" + subresource
+	if versionID != "" {
+		query += "&versionId=" + url.QueryEscape(versionID)
+	}
+	return query
+}
+
+func objectLockHeader(opts []ObjectLockOption) http.Header {
+	header := http.Header{}
+	for _, opt := range opts {
+		opt(header)
+	}
+	return header
+}
@@ -0,0 +1,65 @@
+package gos3
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestS3_PutObjectRetention(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if _, ok := q["retention"]; !ok || q.Get("versionId") != "v1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if r.Header.Get("x-amz-bypass-governance-retention") != "true" {
+			t.Errorf("missing x-amz-bypass-governance-retention header")
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		if r.Header.Get("x-amz-content-sha256") != hex.EncodeToString(sum[:]) {
+			t.Errorf("x-amz-content-sha256 = %s", r.Header.Get("x-amz-content-sha256"))
+		}
+		want := `<Retention><Mode>GOVERNANCE</Mode><RetainUntilDate>2030-01-01T00:00:00Z</RetainUntilDate></Retention>`
+		if string(body) != want {
+			t.Errorf("body = %s, want %s", body, want)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	retainUntil := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := s3.PutObjectRetention("bucket", "test.txt", "v1", RetentionModeGovernance, retainUntil, BypassGovernanceRetention())
+	if err != nil {
+		t.Errorf("S3.PutObjectRetention() error = %v", err)
+	}
+}
+
+func TestS3_PutObjectLegalHold(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["legal-hold"]; !ok || r.URL.Query().Get("versionId") != "" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if r.Header.Get("x-amz-bypass-governance-retention") != "" {
+			t.Errorf("unexpected x-amz-bypass-governance-retention header")
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != `<LegalHold><Status>ON</Status></LegalHold>` {
+			t.Errorf("body = %s", body)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	if err := s3.PutObjectLegalHold("bucket", "test.txt", "", true); err != nil {
+		t.Errorf("S3.PutObjectLegalHold() error = %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import "net/http"
+
+// Option configures an S3 instance created by NewWithOptions.
+type Option func(*S3)
+
+// WithRegion sets the region of the bucket.
+func WithRegion(region string) Option {
+	return func(s3 *S3) {
+		s3.Region = region
+	}
+}
+
+// WithAccessKey sets the access key used for signing.
+func WithAccessKey(accessKey string) Option {
+	return func(s3 *S3) {
+		s3.AccessKey = accessKey
+	}
+}
+
+// WithSecretKey sets the secret key used for signing.
+func WithSecretKey(secretKey string) Option {
+	return func(s3 *S3) {
+		s3.SecretKey = secretKey
+	}
+}
+
+// WithToken sets a Temporary Security Credential token,
+// see SetToken.
+func WithToken(token string) Option {
+	return func(s3 *S3) {
+		s3.SetToken(token)
+	}
+}
+
+// WithEndpoint sets a custom endpoint, see SetEndpoint.
+func WithEndpoint(uri string) Option {
+	return func(s3 *S3) {
+		s3.SetEndpoint(uri)
+	}
+}
+
+// WithClient sets the http client, see SetClient.
+func WithClient(client *http.Client) Option {
+	return func(s3 *S3) {
+		s3.SetClient(client)
+	}
+}
+
+// WithURIFormat sets the format used to build the URL of a bucket.
+// It receives the region and the bucket name as arguments.
+func WithURIFormat(format string) Option {
+	return func(s3 *S3) {
+		s3.URIFormat = format
+	}
+}
+
+// NewWithOptions returns an instance of S3
+// configured by applying opts in order.
+func NewWithOptions(opts ...Option) *S3 {
+	s3 := &S3{
+		URIFormat: defaultURIFormat,
+	}
+	for _, opt := range opts {
+		opt(s3)
+	}
+	return s3
+}
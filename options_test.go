@@ -0,0 +1,39 @@
+package gos3
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	client := &http.Client{}
+	tests := []struct {
+		name  string
+		opts  []Option
+		check func(*S3) bool
+	}{
+		{"defaults", nil, func(s3 *S3) bool {
+			return s3.URIFormat == defaultURIFormat && s3.getClient() == http.DefaultClient
+		}},
+		{"region", []Option{WithRegion("eu-west-1")}, func(s3 *S3) bool {
+			return s3.getURL("bucket") == "https://s3.eu-west-1.amazonaws.com/bucket"
+		}},
+		{"credentials", []Option{WithAccessKey("ak"), WithSecretKey("sk"), WithToken("tok")}, func(s3 *S3) bool {
+			return s3.AccessKey == "ak" && s3.SecretKey == "sk" && s3.Token == "tok"
+		}},
+		{"endpoint", []Option{WithEndpoint("localhost:9000")}, func(s3 *S3) bool {
+			return s3.getURL("bucket") == "https://localhost:9000/bucket"
+		}},
+		{"client", []Option{WithClient(client)}, func(s3 *S3) bool {
+			return s3.getClient() == client
+		}},
+		{"uri format", []Option{WithRegion("r"), WithURIFormat("http://%s.example.com/%s")}, func(s3 *S3) bool {
+			return s3.getURL("bucket") == "http://r.example.com/bucket"
+		}},
+	}
+	for _, tt := range tests {
+		if s3 := NewWithOptions(tt.opts...); !tt.check(s3) {
+			t.Errorf("%s: NewWithOptions() got = %+v", tt.name, s3)
+		}
+	}
+}
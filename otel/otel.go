@@ -0,0 +1,204 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+// Package otel traces the calls made by gos3 with OpenTelemetry.
+//
+// It lives in its own module so that gos3 itself does not
+// depend on OpenTelemetry:
+//
+//	s3 := gos3.New(region, accessKey, secretKey)
+//	otel.Instrument(s3, nil)
+//
+// Every request sent to S3, including retries, is recorded as a span
+// named after the S3 API operation, such as "s3.PutObject", with the
+// s3.bucket, s3.key, http.status_code and net.peer.name attributes.
+package otel
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/animber-coder/gos3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/animber-coder/gos3/otel"
+
+// Instrument wraps the http client of s3 with a transport tracing
+// every request with tp, or the global TracerProvider if tp is nil.
+// It must be called after SetClient and SetURLStyle, as it reads
+// the client and the URL style in use.
+func Instrument(s3 *gos3.S3, tp trace.TracerProvider) {
+	client := http.DefaultClient
+	if s3.Client != nil {
+		client = s3.Client
+	}
+	wrapped := *client
+	wrapped.Transport = NewTransport(client.Transport, tp, s3.URLStyle)
+	s3.SetClient(&wrapped)
+}
+
+// Transport is an http.RoundTripper which
+// traces the requests made to S3.
+type Transport struct {
+	base   http.RoundTripper
+	tracer trace.Tracer
+	style  gos3.URLStyle
+}
+
+// NewTransport returns a Transport sending requests through base,
+// or http.DefaultTransport if base is nil, and tracing them with tp,
+// or the global TracerProvider if tp is nil. style is used to find
+// the bucket and key of the requests.
+func NewTransport(base http.RoundTripper, tp trace.TracerProvider, style gos3.URLStyle) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &Transport{
+		base:   base,
+		tracer: tp.Tracer(instrumentationName),
+		style:  style,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	bucket, key := t.bucketAndKey(req)
+
+	host := req.URL.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	ctx, span := t.tracer.Start(req.Context(), "s3."+operation(req, key),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("s3.bucket", bucket),
+			attribute.String("s3.key", key),
+			attribute.String("net.peer.name", host),
+		),
+	)
+	defer span.End()
+
+	res, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return res, err
+	}
+
+	span.SetAttributes(attribute.Int("http.status_code", res.StatusCode))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		span.SetStatus(codes.Error, http.StatusText(res.StatusCode))
+	}
+	return res, nil
+}
+
+// bucketAndKey returns the bucket and the key addressed by req.
+func (t *Transport) bucketAndKey(req *http.Request) (string, string) {
+	path := strings.TrimPrefix(req.URL.Path, "/")
+	if t.style == gos3.VirtualHostedStyle {
+		host := req.URL.Hostname()
+		if i := strings.Index(host, "."); i > 0 && !strings.HasPrefix(host, "s3.") {
+			return host[:i], path
+		}
+		return "", path
+	}
+
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// bucketSubresources maps the query parameters selecting a bucket
+// configuration to the name used by the S3 API operations.
+var bucketSubresources = map[string]string{
+	"cors":       "Cors",
+	"lifecycle":  "Lifecycle",
+	"location":   "Location",
+	"policy":     "Policy",
+	"tagging":    "Tagging",
+	"versioning": "Versioning",
+}
+
+// operation returns the name of the S3 API operation made by req.
+func operation(req *http.Request, key string) string {
+	query := req.URL.Query()
+	has := func(name string) bool {
+		_, ok := query[name]
+		return ok
+	}
+	verb := map[string]string{
+		http.MethodGet:    "Get",
+		http.MethodPut:    "Put",
+		http.MethodDelete: "Delete",
+	}[req.Method]
+
+	switch {
+	case has("uploads"):
+		if req.Method == http.MethodPost {
+			return "CreateMultipartUpload"
+		}
+		return "ListMultipartUploads"
+	case has("uploadId"):
+		switch req.Method {
+		case http.MethodPut:
+			return "UploadPart"
+		case http.MethodPost:
+			return "CompleteMultipartUpload"
+		case http.MethodDelete:
+			return "AbortMultipartUpload"
+		}
+		return "ListParts"
+	case has("delete") && req.Method == http.MethodPost:
+		return "DeleteObjects"
+	case has("tagging") && key != "":
+		return verb + "ObjectTagging"
+	case has("versions"):
+		return "ListObjectVersions"
+	}
+
+	if key == "" {
+		for param, name := range bucketSubresources {
+			if has(param) && verb != "" {
+				return verb + "Bucket" + name
+			}
+		}
+		switch req.Method {
+		case http.MethodGet:
+			if query.Get("list-type") == "2" {
+				return "ListObjectsV2"
+			}
+			return "ListObjects"
+		case http.MethodPut:
+			return "CreateBucket"
+		case http.MethodDelete:
+			return "DeleteBucket"
+		case http.MethodHead:
+			return "HeadBucket"
+		case http.MethodPost:
+			return "PostObject"
+		}
+		return req.Method
+	}
+
+	switch req.Method {
+	case http.MethodPut:
+		if req.Header.Get("x-amz-copy-source") != "" {
+			return "CopyObject"
+		}
+		return "PutObject"
+	case http.MethodHead:
+		return "HeadObject"
+	}
+	return verb + "Object"
+}
@@ -0,0 +1,85 @@
+package otel
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/animber-coder/gos3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInstrument(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/bucket/missing.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("hello world"))
+	}))
+	defer ts.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	s3 := gos3.New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+	Instrument(s3, tp)
+
+	body, err := s3.FileDownload(gos3.DownloadInput{Bucket: "bucket", ObjectKey: "dir/test.txt"})
+	if err != nil {
+		t.Fatalf("S3.FileDownload() error = %v", err)
+	}
+	body.Close()
+	if _, err := s3.FileHead(gos3.DownloadInput{Bucket: "bucket", ObjectKey: "missing.txt"}); err == nil {
+		t.Fatalf("S3.FileHead() expected an error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if spans[0].Name() != "s3.GetObject" || spans[1].Name() != "s3.HeadObject" {
+		t.Errorf("span names = %s, %s", spans[0].Name(), spans[1].Name())
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs["s3.bucket"].AsString() != "bucket" || attrs["s3.key"].AsString() != "dir/test.txt" ||
+		attrs["http.status_code"].AsInt64() != 200 || attrs["net.peer.name"].AsString() != "127.0.0.1" {
+		t.Errorf("span attributes = %v", spans[0].Attributes())
+	}
+	if spans[0].Status().Code == codes.Error || spans[1].Status().Code != codes.Error {
+		t.Errorf("span statuses = %v, %v", spans[0].Status(), spans[1].Status())
+	}
+}
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		method, url, want string
+	}{
+		{http.MethodPut, "/bucket/key", "PutObject"},
+		{http.MethodDelete, "/bucket/key", "DeleteObject"},
+		{http.MethodPost, "/bucket", "PostObject"},
+		{http.MethodGet, "/bucket?list-type=2", "ListObjectsV2"},
+		{http.MethodPost, "/bucket/key?uploads=", "CreateMultipartUpload"},
+		{http.MethodPut, "/bucket/key?partNumber=1&uploadId=x", "UploadPart"},
+		{http.MethodPost, "/bucket?delete=", "DeleteObjects"},
+		{http.MethodGet, "/bucket/key?tagging=", "GetObjectTagging"},
+		{http.MethodPut, "/bucket?lifecycle=", "PutBucketLifecycle"},
+		{http.MethodHead, "/bucket", "HeadBucket"},
+	}
+	tr := NewTransport(nil, nil, gos3.PathStyle)
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.url, nil)
+		_, key := tr.bucketAndKey(req)
+		if got := operation(req, key); got != tt.want {
+			t.Errorf("operation(%s %s) = %s, want %s", tt.method, tt.url, got, tt.want)
+		}
+	}
+}
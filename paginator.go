@@ -0,0 +1,101 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Paginator fetches the pages of a list operation one at a time,
+// keeping track of the continuation token or markers in between.
+// It is driven with a loop:
+//
+//	p := gos3.NewListObjectsPaginator(s3, in)
+//	for p.HasMore() {
+//		page, err := p.NextPage()
+//		...
+//	}
+//
+// A Paginator is not safe for concurrent use.
+type Paginator[T any] struct {
+	// fetch returns the next page and
+	// whether any page follows it.
+	fetch func() (T, bool, error)
+	done  bool
+}
+
+// HasMore reports whether NextPage has pages left to return.
+func (p *Paginator[T]) HasMore() bool {
+	return !p.done
+}
+
+// NextPage fetches and returns the next page. If it fails, the
+// same page is fetched again by the next call. Once the last
+// page has been returned, it returns ErrNoMorePages.
+func (p *Paginator[T]) NextPage() (T, error) {
+	if p.done {
+		var zero T
+		return zero, ErrNoMorePages
+	}
+	page, more, err := p.fetch()
+	if err != nil {
+		return page, err
+	}
+	p.done = !more
+	return page, nil
+}
+
+// NewListObjectsPaginator returns a Paginator over the pages
+// of ListObjects, starting from in.ContinuationToken if set.
+func NewListObjectsPaginator(s3 *S3, in ListInput) *Paginator[ListOutput] {
+	return &Paginator[ListOutput]{fetch: func() (ListOutput, bool, error) {
+		out, err := s3.ListObjects(in)
+		if err != nil {
+			return ListOutput{}, false, err
+		}
+		in.ContinuationToken = out.NextContinuationToken
+		return out, out.IsTruncated && out.NextContinuationToken != "", nil
+	}}
+}
+
+// NewListObjectVersionsPaginator returns a Paginator over the pages
+// of the versions of the objects whose key starts with prefix,
+// as listed by ListObjectVersions.
+func NewListObjectVersionsPaginator(s3 *S3, bucket, prefix string) *Paginator[[]ObjectVersion] {
+	query := url.Values{}
+	query.Set("versions", "")
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	return &Paginator[[]ObjectVersion]{fetch: func() ([]ObjectVersion, bool, error) {
+		result, err := s3.listObjectVersions(bucket, query)
+		if err != nil {
+			return nil, false, err
+		}
+		query.Set("key-marker", result.NextKeyMarker)
+		query.Set("version-id-marker", result.NextVersionIDMarker)
+		return result.Versions, result.IsTruncated && result.NextKeyMarker != "", nil
+	}}
+}
+
+// NewListMultipartUploadsPaginator returns a Paginator over the pages
+// of the multipart uploads in progress for the keys starting with
+// prefix, as listed by ListIncompleteMultiparts.
+func NewListMultipartUploadsPaginator(s3 *S3, bucket, prefix string) *Paginator[[]IncompleteUpload] {
+	query := url.Values{}
+	query.Set("uploads", "")
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	return &Paginator[[]IncompleteUpload]{fetch: func() ([]IncompleteUpload, bool, error) {
+		var result listMultipartUploadsResult
+		if err := s3.doXML(http.MethodGet, bucket, "", "?"+query.Encode(), nil, nil, &result); err != nil {
+			return nil, false, err
+		}
+		query.Set("key-marker", result.NextKeyMarker)
+		query.Set("upload-id-marker", result.NextUploadIDMarker)
+		return result.Uploads, result.IsTruncated && result.NextKeyMarker != "", nil
+	}}
+}
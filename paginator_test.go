@@ -0,0 +1,79 @@
+package gos3
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPaginator(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		_, versions := q["versions"]
+		_, uploads := q["uploads"]
+		switch {
+		case q.Get("list-type") == "2" && q.Get("continuation-token") == "":
+			io.WriteString(w, `<ListBucketResult><Contents><Key>a.txt</Key></Contents>`+
+				`<IsTruncated>true</IsTruncated><NextContinuationToken>next</NextContinuationToken></ListBucketResult>`)
+		case q.Get("list-type") == "2" && q.Get("continuation-token") == "next":
+			io.WriteString(w, `<ListBucketResult><Contents><Key>b.txt</Key></Contents><IsTruncated>false</IsTruncated></ListBucketResult>`)
+		case versions && q.Get("key-marker") == "":
+			io.WriteString(w, `<ListVersionsResult><Version><Key>a.txt</Key><VersionId>v1</VersionId></Version>`+
+				`<IsTruncated>true</IsTruncated><NextKeyMarker>a.txt</NextKeyMarker><NextVersionIdMarker>v1</NextVersionIdMarker></ListVersionsResult>`)
+		case versions && q.Get("key-marker") == "a.txt" && q.Get("version-id-marker") == "v1":
+			io.WriteString(w, `<ListVersionsResult><Version><Key>a.txt</Key><VersionId>v2</VersionId></Version><IsTruncated>false</IsTruncated></ListVersionsResult>`)
+		case uploads:
+			io.WriteString(w, `<ListMultipartUploadsResult><Upload><Key>big.bin</Key><UploadId>upload-id</UploadId></Upload>`+
+				`<IsTruncated>false</IsTruncated></ListMultipartUploadsResult>`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	var keys []string
+	objects := NewListObjectsPaginator(s3, ListInput{Bucket: "bucket"})
+	for objects.HasMore() {
+		page, err := objects.NextPage()
+		if err != nil {
+			t.Fatalf("Paginator.NextPage() error = %v", err)
+		}
+		for _, obj := range page.Objects {
+			keys = append(keys, obj.Key)
+		}
+	}
+	if want := []string{"a.txt", "b.txt"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("ListObjects pages got keys %v, want %v", keys, want)
+	}
+	if _, err := objects.NextPage(); !errors.Is(err, ErrNoMorePages) {
+		t.Errorf("Paginator.NextPage() error = %v, want ErrNoMorePages", err)
+	}
+
+	var ids []string
+	versions := NewListObjectVersionsPaginator(s3, "bucket", "")
+	for versions.HasMore() {
+		page, err := versions.NextPage()
+		if err != nil {
+			t.Fatalf("Paginator.NextPage() error = %v", err)
+		}
+		for _, v := range page {
+			ids = append(ids, v.VersionID)
+		}
+	}
+	if want := []string{"v1", "v2"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ListObjectVersions pages got versions %v, want %v", ids, want)
+	}
+
+	uploads := NewListMultipartUploadsPaginator(s3, "bucket", "")
+	page, err := uploads.NextPage()
+	if err != nil || len(page) != 1 || page[0].UploadID != "upload-id" || uploads.HasMore() {
+		t.Errorf("ListMultipartUploads page = %v, %v, more = %v", page, err, uploads.HasMore())
+	}
+}
@@ -40,6 +40,15 @@ type UploadPolicies struct {
 	Form map[string]string
 }
 
+// PresignedPost contains what a browser needs to upload
+// a file directly to S3 with an HTML form: the form is
+// submitted to URL with Fields as hidden inputs, followed
+// by the file in an input named "file".
+type PresignedPost struct {
+	URL    string
+	Fields map[string]string
+}
+
 // PolicyJSON is policy rule
 type PolicyJSON struct {
 	Expiration string        `json:"expiration"`
@@ -52,6 +61,7 @@ const (
 	shortTimeFormat          = "20060102"
 	algorithm                = "AWS4-HMAC-SHA256"
 	serviceName              = "s3"
+	unsignedPayload          = "UNSIGNED-PAYLOAD"
 
 	defaultUploadURLFormat = "http://%s.s3.amazonaws.com/" // <bucketName>
 	defaultExpirationHour  = 1 * time.Hour
@@ -68,9 +78,12 @@ var newLine = []byte{'\n'}
 // policy and signing keys with the signature returns the upload policy.
 // https://docs.aws.amazon.com/ja_jp/AmazonS3/latest/API/sigv4-authentication-HTTPPOST.html
 func (s3 *S3) CreateUploadPolicies(uploadConfig UploadConfig) (UploadPolicies, error) {
+	s3.mu.RLock()
+	defer s3.mu.RUnlock()
+
 	nowTime := nowTime()
 	credential := string(s3.buildCredential(nowTime))
-	data, err := buildUploadSign(nowTime, credential, uploadConfig)
+	data, err := buildUploadSign(nowTime, credential, s3.Token, uploadConfig)
 	if err != nil {
 		return UploadPolicies{}, err
 	}
@@ -107,6 +120,10 @@ func (s3 *S3) CreateUploadPolicies(uploadConfig UploadConfig) (UploadPolicies, e
 		form["x-amz-acl"] = uploadConfig.ACL
 	}
 
+	if s3.Token != "" {
+		form["x-amz-security-token"] = s3.Token
+	}
+
 	for k, v := range uploadConfig.MetaData {
 		form[k] = v
 	}
@@ -116,7 +133,29 @@ func (s3 *S3) CreateUploadPolicies(uploadConfig UploadConfig) (UploadPolicies, e
 	}, nil
 }
 
-func buildUploadSign(nowTime time.Time, credential string, uploadConfig UploadConfig) ([]byte, error) {
+// CreatePresignedPost creates a presigned POST policy which lets
+// a browser upload a file directly to S3, without it going through
+// the server. If cfg.UploadURL is not set, the URL of the bucket
+// is used.
+func (s3 *S3) CreatePresignedPost(cfg UploadConfig) (PresignedPost, error) {
+	if cfg.UploadURL == "" {
+		uploadURL, _, err := s3.resolve(cfg.BucketName, "")
+		if err != nil {
+			return PresignedPost{}, err
+		}
+		cfg.UploadURL = uploadURL
+	}
+	policies, err := s3.CreateUploadPolicies(cfg)
+	if err != nil {
+		return PresignedPost{}, err
+	}
+	return PresignedPost{
+		URL:    policies.URL,
+		Fields: policies.Form,
+	}, nil
+}
+
+func buildUploadSign(nowTime time.Time, credential, token string, uploadConfig UploadConfig) ([]byte, error) {
 	// essential conditions
 	conditions := []interface{}{
 		map[string]string{"bucket": uploadConfig.BucketName},
@@ -137,6 +176,10 @@ func buildUploadSign(nowTime time.Time, credential string, uploadConfig UploadCo
 		conditions = append(conditions, map[string]string{"x-amz-acl": uploadConfig.ACL})
 	}
 
+	if token != "" {
+		conditions = append(conditions, map[string]string{"x-amz-security-token": token})
+	}
+
 	for k, v := range uploadConfig.MetaData {
 		conditions = append(conditions, map[string]string{k: v})
 	}
@@ -152,7 +195,7 @@ func buildUploadSign(nowTime time.Time, credential string, uploadConfig UploadCo
 	})
 }
 
-func (s3 S3) buildCredential(nowTime time.Time) []byte {
+func (s3 *S3) buildCredential(nowTime time.Time) []byte {
 	var b bytes.Buffer
 	b.WriteString(s3.AccessKey)
 	b.WriteRune('/')
@@ -166,7 +209,7 @@ func (s3 S3) buildCredential(nowTime time.Time) []byte {
 	return b.Bytes()
 }
 
-func (s3 S3) buildCredentialWithoutKey(nowTime time.Time) []byte {
+func (s3 *S3) buildCredentialWithoutKey(nowTime time.Time) []byte {
 	var b bytes.Buffer
 	b.WriteString(nowTime.Format(shortTimeFormat))
 	b.WriteRune('/')
@@ -0,0 +1,40 @@
+package gos3
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+func TestS3_CreatePresignedPost(t *testing.T) {
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	post, err := s3.CreatePresignedPost(UploadConfig{
+		BucketName:  "bucket",
+		ObjectKey:   "uploads/avatar.png",
+		ContentType: "image/png",
+		FileSize:    1024,
+	})
+	if err != nil {
+		t.Fatalf("S3.CreatePresignedPost() error = %v", err)
+	}
+	if post.URL != "https://s3.us-east-1.amazonaws.com/bucket" {
+		t.Errorf("S3.CreatePresignedPost() URL = %v", post.URL)
+	}
+	for _, field := range []string{"key", "Content-Type", "Policy", "X-Amz-Signature", "X-Amz-Credential"} {
+		if post.Fields[field] == "" {
+			t.Errorf("S3.CreatePresignedPost() missing field %s", field)
+		}
+	}
+
+	data, err := base64.StdEncoding.DecodeString(post.Fields["Policy"])
+	if err != nil {
+		t.Fatalf("invalid policy encoding: %v", err)
+	}
+	var policy PolicyJSON
+	if err := json.Unmarshal(data, &policy); err != nil {
+		t.Fatalf("invalid policy: %v", err)
+	}
+	if policy.Expiration == "" || len(policy.Conditions) == 0 {
+		t.Errorf("S3.CreatePresignedPost() policy = %s", data)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
+	"net/http"
 	"net/url"
 	"sort"
 	"strconv"
@@ -14,6 +16,10 @@ import (
 const (
 	defaultPresignedHost = "s3.amazonaws.com" // <bucket>
 	defaultProtocol      = "https://"         // <bucket>
+
+	// maxPresignExpiry is the longest validity
+	// S3 accepts for a Presigned URL.
+	maxPresignExpiry = 7 * 24 * time.Hour
 )
 
 // PresignedInput is passed to GeneratePresignedURL as a parameter.
@@ -26,12 +32,19 @@ type PresignedInput struct {
 	ExpirySeconds int
 	Protocol      string
 	Endpoint      string
+
+	// ExtraQuery is added to the query string and signed,
+	// eg. to address a part of a multipart upload.
+	ExtraQuery map[string]string
 }
 
 // GeneratePresignedURL creates a Presigned URL that can be used
 // for Authentication using Query Parameters.
 // (https://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-query-string-auth.html)
 func (s3 *S3) GeneratePresignedURL(in PresignedInput) string {
+	s3.mu.RLock()
+	defer s3.mu.RUnlock()
+
 	var (
 		nowTime = nowTime()
 
@@ -89,41 +102,37 @@ func (s3 *S3) GeneratePresignedURL(in PresignedInput) string {
 	if s3.Token != "" {
 		queryString["X-Amz-Security-Token"] = s3.Token
 	}
-
-	// We need to have a sorted order,
-	// for QueryStrings and SignedHeaders
-	sortedQS := make([]string, 0, len(queryString))
-	for name := range queryString {
-		sortedQS = append(sortedQS, name)
+	if s3.RequesterPays {
+		queryString["x-amz-request-payer"] = "requester"
+	}
+	for k, v := range in.ExtraQuery {
+		queryString[k] = v
 	}
-	sort.Strings(sortedQS) //sort by key
 
 	sortedSH := make([]string, 0, len(signedHeaders))
 	for name := range signedHeaders {
 		sortedSH = append(sortedSH, name)
 	}
 	sort.Strings(sortedSH) //sort by key
+	queryString["X-Amz-SignedHeaders"] = strings.Join(sortedSH, ";")
 
-	// Proceed to write canonical query params
-	for _, k := range sortedQS {
-		// HTTP Verb
-		h.Write([]byte(url.QueryEscape(k)))
-		h.Write([]byte{'='})
-		h.Write([]byte(url.QueryEscape(string(queryString[k]))))
-		h.Write([]byte{'&'})
+	// We need to have a sorted order for QueryStrings,
+	// which must include X-Amz-SignedHeaders and any
+	// ExtraQuery in the right position.
+	sortedQS := make([]string, 0, len(queryString))
+	for name := range queryString {
+		sortedQS = append(sortedQS, name)
 	}
+	sort.Strings(sortedQS) //sort by key
 
-	h.Write([]byte("X-Amz-SignedHeaders="))
-	// Add Signed Headers to Query String
-	first := true
-	for i := 0; i < len(sortedSH); i++ {
-		if first {
-			h.Write([]byte(url.QueryEscape(sortedSH[i])))
-			first = false
-		} else {
-			h.Write([]byte{';'})
-			h.Write([]byte(url.QueryEscape(sortedSH[i])))
+	// Proceed to write canonical query params
+	for i, k := range sortedQS {
+		if i > 0 {
+			h.Write([]byte{'&'})
 		}
+		h.Write([]byte(uriEncode(k)))
+		h.Write([]byte{'='})
+		h.Write([]byte(uriEncode(queryString[k])))
 	}
 	h.Write(newLine)
 	// End QueryString Params
@@ -139,21 +148,12 @@ func (s3 *S3) GeneratePresignedURL(in PresignedInput) string {
 	// End Canonical Headers
 
 	// Start Signed Headers
-	first = true
-	for i := 0; i < len(sortedSH); i++ {
-		if first {
-			h.Write([]byte(url.QueryEscape(sortedSH[i])))
-			first = false
-		} else {
-			h.Write([]byte{';'})
-			h.Write([]byte(url.QueryEscape(sortedSH[i])))
-		}
-	}
+	h.Write([]byte(queryString["X-Amz-SignedHeaders"]))
 	h.Write(newLine)
 	// End Canonical Headers
 
 	// Mention Unsigned Payload
-	h.Write([]byte("UNSIGNED-PAYLOAD"))
+	h.Write([]byte(unsignedPayload))
 
 	// canonicalReq := h.Bytes()
 	// Start StringToSign
@@ -204,25 +204,165 @@ func (s3 *S3) GeneratePresignedURL(in PresignedInput) string {
 	// We don't need to have a sorted order here,
 	// but just to preserve tests.
 	for i := 0; i < len(sortedQS); i++ {
-		b.WriteString(url.QueryEscape(sortedQS[i]))
-		b.WriteRune('=')
-		b.WriteString(url.QueryEscape(string(queryString[sortedQS[i]])))
-		b.WriteRune('&')
-	}
-	b.WriteString("X-Amz-SignedHeaders")
-	b.WriteRune('=')
-	first = true
-	for i := 0; i < len(sortedSH); i++ {
-		if first {
-			b.WriteString(url.QueryEscape(sortedSH[i]))
-			first = false
-		} else {
-			b.WriteRune(';')
-			b.WriteString(url.QueryEscape(sortedSH[i]))
+		if i > 0 {
+			b.WriteRune('&')
 		}
+		b.WriteString(uriEncode(sortedQS[i]))
+		b.WriteRune('=')
+		b.WriteString(uriEncode(queryString[sortedQS[i]]))
 	}
 	b.WriteString("&X-Amz-Signature=")
 	b.WriteString(signature)
 
 	return b.String()
 }
+
+// PresignOptions overrides the headers of the response to
+// a Presigned URL, eg. to make a browser save the object
+// under another name with ResponseContentDisposition.
+type PresignOptions struct {
+	ResponseContentType        string
+	ResponseContentDisposition string
+	ResponseCacheControl       string
+	ResponseExpires            string
+}
+
+// query returns the signed query parameters
+// overriding the headers of the response.
+func (o PresignOptions) query() map[string]string {
+	query := map[string]string{}
+	for k, v := range map[string]string{
+		"response-content-type":        o.ResponseContentType,
+		"response-content-disposition": o.ResponseContentDisposition,
+		"response-cache-control":       o.ResponseCacheControl,
+		"response-expires":             o.ResponseExpires,
+	} {
+		if v != "" {
+			query[k] = v
+		}
+	}
+	return query
+}
+
+// PresignGetURL creates a Presigned URL that can be used to download
+// the object without credentials, eg. in a browser, until expiry
+// has passed. expiry must not be longer than 7 days. The headers
+// set in opts, if any, override those of the response, the fields
+// of later options taking precedence.
+func (s3 *S3) PresignGetURL(bucket, objectKey string, expiry time.Duration, opts ...PresignOptions) (string, error) {
+	query := map[string]string{}
+	for _, o := range opts {
+		for k, v := range o.query() {
+			query[k] = v
+		}
+	}
+	return s3.presignURL(http.MethodGet, bucket, objectKey, expiry, nil, query)
+}
+
+// PresignHeadURL creates a Presigned URL that can be used to retrieve
+// the metadata of the object, such as its ETag, Content-Type and
+// Content-Length, with a HEAD request without credentials and
+// without downloading its body, until expiry has passed.
+// expiry must not be longer than 7 days.
+func (s3 *S3) PresignHeadURL(bucket, objectKey string, expiry time.Duration) (string, error) {
+	return s3.presignURL(http.MethodHead, bucket, objectKey, expiry, nil, nil)
+}
+
+// PresignDeleteURL creates a Presigned URL that can be used to delete
+// the object with a DELETE request without credentials, until expiry
+// has passed. expiry must not be longer than 7 days.
+//
+// Anyone holding the URL can delete the object, and it cannot be
+// revoked other than by revoking the credentials used to sign it.
+// Never give delete URLs to untrusted parties, and keep expiry short.
+func (s3 *S3) PresignDeleteURL(bucket, objectKey string, expiry time.Duration) (string, error) {
+	return s3.presignURL(http.MethodDelete, bucket, objectKey, expiry, nil, nil)
+}
+
+// PresignPutURL creates a Presigned URL that can be used to upload
+// the object directly to S3, eg. from a browser or a mobile app,
+// until expiry has passed. expiry must not be longer than 7 days.
+//
+// The Content-Type header is part of the signature, so the client
+// must send the same contentType in its request, otherwise S3 rejects
+// the upload with SignatureDoesNotMatch.
+func (s3 *S3) PresignPutURL(bucket, objectKey, contentType string, expiry time.Duration) (string, error) {
+	return s3.presignURL(http.MethodPut, bucket, objectKey, expiry, map[string]string{
+		"content-type": contentType,
+	}, nil)
+}
+
+// PresignUploadPartURL creates a Presigned URL that can be used to
+// upload a part of the multipart upload uploadID, started with
+// InitiateMultipartUpload, eg. from a browser. The ETag header of
+// the response must be collected to complete the upload.
+func (s3 *S3) PresignUploadPartURL(bucket, objectKey, uploadID string, partNumber int, expiry time.Duration) (string, error) {
+	if partNumber < 1 || partNumber > maxParts {
+		return "", fmt.Errorf("part number %d is not between 1 and %d", partNumber, maxParts)
+	}
+	return s3.presignURL(http.MethodPut, bucket, objectKey, expiry, nil, map[string]string{
+		"partNumber": strconv.Itoa(partNumber),
+		"uploadId":   uploadID,
+	})
+}
+
+// PresignCompleteMultipartURL creates a Presigned URL that can be used
+// to complete the multipart upload uploadID with a POST request whose
+// body is the CompleteMultipartUpload XML listing the uploaded parts.
+func (s3 *S3) PresignCompleteMultipartURL(bucket, objectKey, uploadID string, expiry time.Duration) (string, error) {
+	return s3.presignURL(http.MethodPost, bucket, objectKey, expiry, nil, map[string]string{
+		"uploadId": uploadID,
+	})
+}
+
+// PresignAbortMultipartURL creates a Presigned URL that can be used
+// to abort the multipart upload uploadID with a DELETE request.
+func (s3 *S3) PresignAbortMultipartURL(bucket, objectKey, uploadID string, expiry time.Duration) (string, error) {
+	return s3.presignURL(http.MethodDelete, bucket, objectKey, expiry, nil, map[string]string{
+		"uploadId": uploadID,
+	})
+}
+
+func (s3 *S3) presignURL(method, bucket, objectKey string, expiry time.Duration, headers, query map[string]string) (string, error) {
+	if expiry < time.Second {
+		return "", fmt.Errorf("expiry %s is shorter than a second", expiry)
+	}
+	if expiry > maxPresignExpiry {
+		return "", fmt.Errorf("expiry %s is longer than the maximum of %s", expiry, maxPresignExpiry)
+	}
+
+	return s3.GeneratePresignedURL(PresignedInput{
+		Bucket:        bucket,
+		ObjectKey:     objectKey,
+		Method:        method,
+		ExtraHeaders:  headers,
+		ExtraQuery:    query,
+		ExpirySeconds: int(expiry / time.Second),
+	}), nil
+}
+
+// ValidatePresignedURL checks that a Presigned URL, eg. received from
+// another service, has not expired yet. It only looks at X-Amz-Date and
+// X-Amz-Expires, not at the signature, and makes no call to S3.
+// ErrPresignedURLExpired is returned if the URL has expired.
+func ValidatePresignedURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	query := u.Query()
+
+	date, err := time.Parse(amzDateISO8601TimeFormat, query.Get("X-Amz-Date"))
+	if err != nil {
+		return fmt.Errorf("invalid X-Amz-Date: %w", err)
+	}
+	expires, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+	if err != nil {
+		return fmt.Errorf("invalid X-Amz-Expires: %w", err)
+	}
+
+	if nowTime().After(date.Add(time.Duration(expires) * time.Second)) {
+		return ErrPresignedURLExpired
+	}
+	return nil
+}
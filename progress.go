@@ -0,0 +1,33 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import "io"
+
+// progressReader calls fn with the cumulative
+// number of bytes read after every Read.
+type progressReader struct {
+	r     io.Reader
+	fn    func(n, total int64)
+	n     int64
+	total int64
+}
+
+// withProgress decorates r to report progress to fn.
+// If fn is nil, r is returned as is.
+func withProgress(r io.Reader, total int64, fn func(n, total int64)) io.Reader {
+	if fn == nil {
+		return r
+	}
+	return &progressReader{r: r, fn: fn, total: total}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.n += int64(n)
+		p.fn(p.n, p.total)
+	}
+	return n, err
+}
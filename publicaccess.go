@@ -0,0 +1,55 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// PublicAccessBlockConfig blocks public access to a bucket
+// and its objects, regardless of their ACLs and policies.
+type PublicAccessBlockConfig struct {
+	// BlockPublicAcls rejects requests setting public ACLs.
+	BlockPublicAcls bool `xml:"BlockPublicAcls"`
+	// IgnorePublicAcls ignores the public ACLs already set.
+	IgnorePublicAcls bool `xml:"IgnorePublicAcls"`
+	// BlockPublicPolicy rejects bucket policies granting public access.
+	BlockPublicPolicy bool `xml:"BlockPublicPolicy"`
+	// RestrictPublicBuckets restricts the access granted by a public
+	// bucket policy to AWS services and the bucket owner's account.
+	RestrictPublicBuckets bool `xml:"RestrictPublicBuckets"`
+}
+
+// publicAccessBlockConfiguration is the XML
+// representation of a PublicAccessBlockConfig.
+type publicAccessBlockConfiguration struct {
+	XMLName xml.Name `xml:"PublicAccessBlockConfiguration"`
+	PublicAccessBlockConfig
+}
+
+// PutPublicAccessBlock makes a PUT call to replace the
+// public access block configuration of a bucket with cfg.
+func (s3 *S3) PutPublicAccessBlock(bucket string, cfg PublicAccessBlockConfig) error {
+	return s3.doXML(http.MethodPut, bucket, "", "?publicAccessBlock", nil, publicAccessBlockConfiguration{
+		PublicAccessBlockConfig: cfg,
+	}, nil)
+}
+
+// GetPublicAccessBlock makes a GET call and returns the public access
+// block configuration of a bucket. If the bucket has none, the returned
+// error matches ErrNotFound.
+func (s3 *S3) GetPublicAccessBlock(bucket string) (PublicAccessBlockConfig, error) {
+	var config publicAccessBlockConfiguration
+	if err := s3.doXML(http.MethodGet, bucket, "", "?publicAccessBlock", nil, nil, &config); err != nil {
+		return PublicAccessBlockConfig{}, err
+	}
+	return config.PublicAccessBlockConfig, nil
+}
+
+// DeletePublicAccessBlock makes a DELETE call to remove
+// the public access block configuration of a bucket.
+func (s3 *S3) DeletePublicAccessBlock(bucket string) error {
+	return s3.doXML(http.MethodDelete, bucket, "", "?publicAccessBlock", nil, nil, nil)
+}
@@ -0,0 +1,61 @@
+package gos3
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestS3_PublicAccessBlock(t *testing.T) {
+	var stored []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["publicAccessBlock"]; !ok || r.URL.Path != "/bucket" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			stored, _ = ioutil.ReadAll(r.Body)
+		case http.MethodGet:
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`<Error><Code>NoSuchPublicAccessBlockConfiguration</Code></Error>`))
+				return
+			}
+			w.Write(stored)
+		case http.MethodDelete:
+			stored = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	cfg := PublicAccessBlockConfig{BlockPublicAcls: true, IgnorePublicAcls: true, BlockPublicPolicy: true}
+	if err := s3.PutPublicAccessBlock("bucket", cfg); err != nil {
+		t.Fatalf("S3.PutPublicAccessBlock() error = %v", err)
+	}
+	want := `<PublicAccessBlockConfiguration><BlockPublicAcls>true</BlockPublicAcls><IgnorePublicAcls>true</IgnorePublicAcls>` +
+		`<BlockPublicPolicy>true</BlockPublicPolicy><RestrictPublicBuckets>false</RestrictPublicBuckets></PublicAccessBlockConfiguration>`
+	if string(stored) != want {
+		t.Errorf("S3.PutPublicAccessBlock() sent = %s, want %s", stored, want)
+	}
+
+	got, err := s3.GetPublicAccessBlock("bucket")
+	if err != nil {
+		t.Fatalf("S3.GetPublicAccessBlock() error = %v", err)
+	}
+	if got != cfg {
+		t.Errorf("S3.GetPublicAccessBlock() = %+v, want %+v", got, cfg)
+	}
+
+	if err := s3.DeletePublicAccessBlock("bucket"); err != nil {
+		t.Fatalf("S3.DeletePublicAccessBlock() error = %v", err)
+	}
+	if _, err := s3.GetPublicAccessBlock("bucket"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("S3.GetPublicAccessBlock() error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,74 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// RollbackError is returned by AtomicReplace when the new object
+// was uploaded but the old one could not be deleted.
+type RollbackError struct {
+	// Key is the key of the new object, which
+	// AtomicReplace attempted to delete.
+	Key string
+	// DeleteErr is the error deleting the old object.
+	DeleteErr error
+	// RollbackErr is the error deleting the new object,
+	// nil if it was deleted.
+	RollbackErr error
+}
+
+func (e *RollbackError) Error() string {
+	if e.RollbackErr == nil {
+		return fmt.Sprintf("failed to delete the old object, deleted %s: %v", e.Key, e.DeleteErr)
+	}
+	return fmt.Sprintf("failed to delete the old object: %v, and failed to delete %s: %v", e.DeleteErr, e.Key, e.RollbackErr)
+}
+
+// Unwrap returns the error deleting the old object.
+func (e *RollbackError) Unwrap() error {
+	return e.DeleteErr
+}
+
+// AtomicReplace replaces the object oldKey of u.Bucket with the body
+// of u. The body is uploaded with FilePut under u.ObjectKey suffixed
+// with a random UUID, so that it never overwrites an existing object,
+// and oldKey is only deleted once the upload succeeded. If the delete
+// fails, the new object is deleted as well and the returned error
+// is a *RollbackError. On success, the Key of the returned
+// UploadResponse is the key of the new object.
+func (s3 *S3) AtomicReplace(u UploadInput, oldKey string) (UploadResponse, error) {
+	uuid, err := newUUID()
+	if err != nil {
+		return UploadResponse{}, err
+	}
+	u.ObjectKey += "-" + uuid
+
+	resp, err := s3.FilePut(u)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+
+	if err := s3.FileDelete(DeleteInput{Bucket: u.Bucket, ObjectKey: oldKey}); err != nil {
+		return UploadResponse{}, &RollbackError{
+			Key:         u.ObjectKey,
+			DeleteErr:   err,
+			RollbackErr: s3.FileDelete(DeleteInput{Bucket: u.Bucket, ObjectKey: u.ObjectKey}),
+		}
+	}
+	return resp, nil
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
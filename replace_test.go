@@ -0,0 +1,71 @@
+package gos3
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestS3_AtomicReplace(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		objects = map[string]string{"/bucket/report.csv": "old", "/bucket/locked.csv": "old"}
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := ioutil.ReadAll(r.Body)
+			objects[r.URL.Path] = string(data)
+		case http.MethodDelete:
+			if r.URL.Path == "/bucket/locked.csv" {
+				w.WriteHeader(http.StatusForbidden)
+				io.WriteString(w, `<Error><Code>AccessDenied</Code></Error>`)
+				return
+			}
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	resp, err := s3.AtomicReplace(UploadInput{
+		Bucket:    "bucket",
+		ObjectKey: "report.csv",
+		Body:      strings.NewReader("new"),
+	}, "report.csv")
+	if err != nil {
+		t.Fatalf("S3.AtomicReplace() error = %v", err)
+	}
+	uuid := regexp.MustCompile(`^report\.csv-[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if !uuid.MatchString(resp.Key) {
+		t.Errorf("S3.AtomicReplace() uploaded to %s", resp.Key)
+	}
+	if _, ok := objects["/bucket/report.csv"]; ok || objects["/bucket/"+resp.Key] != "new" || len(objects) != 2 {
+		t.Errorf("S3.AtomicReplace() left objects %v", objects)
+	}
+
+	_, err = s3.AtomicReplace(UploadInput{
+		Bucket:    "bucket",
+		ObjectKey: "locked.csv",
+		Body:      strings.NewReader("new"),
+	}, "locked.csv")
+	var rollbackErr *RollbackError
+	if !errors.As(err, &rollbackErr) || rollbackErr.RollbackErr != nil || !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("S3.AtomicReplace() error = %v, want a RollbackError matching ErrAccessDenied", err)
+	}
+	if _, ok := objects["/bucket/"+rollbackErr.Key]; ok || objects["/bucket/locked.csv"] != "old" {
+		t.Errorf("S3.AtomicReplace() did not roll back, objects %v", objects)
+	}
+}
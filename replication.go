@@ -0,0 +1,105 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// ReplicationConfig configures the replication of the objects
+// of a bucket to other buckets. Versioning must be enabled on
+// the source and destination buckets.
+type ReplicationConfig struct {
+	// Role is the ARN of the IAM role assumed
+	// by S3 to replicate the objects.
+	Role  string
+	Rules []ReplicationRule
+}
+
+// ReplicationRule replicates the objects whose key starts with
+// Prefix to DestinationBucket, the ARN of a bucket,
+// eg. arn:aws:s3:::replica.
+type ReplicationRule struct {
+	ID string
+	// Priority decides which rule applies
+	// when several rules match an object.
+	Priority int
+	// Status is Enabled or Disabled.
+	Status string
+	Prefix string
+
+	DestinationBucket string
+	// StorageClass of the replicas, if not set
+	// the one of the source object is used.
+	StorageClass string
+
+	// DeleteMarkerReplication replicates delete markers.
+	DeleteMarkerReplication bool
+}
+
+// replicationConfiguration is the XML
+// representation of a ReplicationConfig.
+type replicationConfiguration struct {
+	XMLName xml.Name          `xml:"ReplicationConfiguration"`
+	Role    string            `xml:"Role"`
+	Rules   []replicationRule `xml:"Rule"`
+}
+
+type replicationRule struct {
+	ID                      string `xml:"ID,omitempty"`
+	Priority                int    `xml:"Priority"`
+	Status                  string `xml:"Status"`
+	Prefix                  string `xml:"Filter>Prefix"`
+	DeleteMarkerReplication string `xml:"DeleteMarkerReplication>Status"`
+	DestinationBucket       string `xml:"Destination>Bucket"`
+	StorageClass            string `xml:"Destination>StorageClass,omitempty"`
+}
+
+// PutBucketReplication makes a PUT call to replace the replication
+// configuration of a bucket with cfg. If versioning is not enabled
+// on the bucket, the returned error matches ErrVersioningNotEnabled.
+func (s3 *S3) PutBucketReplication(bucket string, cfg ReplicationConfig) error {
+	config := replicationConfiguration{Role: cfg.Role}
+	for _, r := range cfg.Rules {
+		deleteMarkers := "Disabled"
+		if r.DeleteMarkerReplication {
+			deleteMarkers = "Enabled"
+		}
+		config.Rules = append(config.Rules, replicationRule{
+			ID:                      r.ID,
+			Priority:                r.Priority,
+			Status:                  r.Status,
+			Prefix:                  r.Prefix,
+			DeleteMarkerReplication: deleteMarkers,
+			DestinationBucket:       r.DestinationBucket,
+			StorageClass:            r.StorageClass,
+		})
+	}
+	return s3.doXML(http.MethodPut, bucket, "", "?replication", nil, config, nil)
+}
+
+// GetBucketReplication makes a GET call and returns the replication
+// configuration of a bucket. If the bucket has none, the returned
+// error matches ErrNotFound.
+func (s3 *S3) GetBucketReplication(bucket string) (ReplicationConfig, error) {
+	var config replicationConfiguration
+	if err := s3.doXML(http.MethodGet, bucket, "", "?replication", nil, nil, &config); err != nil {
+		return ReplicationConfig{}, err
+	}
+
+	cfg := ReplicationConfig{Role: config.Role}
+	for _, r := range config.Rules {
+		cfg.Rules = append(cfg.Rules, ReplicationRule{
+			ID:                      r.ID,
+			Priority:                r.Priority,
+			Status:                  r.Status,
+			Prefix:                  r.Prefix,
+			DestinationBucket:       r.DestinationBucket,
+			StorageClass:            r.StorageClass,
+			DeleteMarkerReplication: r.DeleteMarkerReplication == "Enabled",
+		})
+	}
+	return cfg, nil
+}
@@ -0,0 +1,65 @@
+package gos3
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestS3_BucketReplication(t *testing.T) {
+	var stored []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["replication"]; !ok {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch {
+		case r.URL.Path == "/unversioned":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`<Error><Code>InvalidRequest</Code><Message>Versioning must be 'Enabled' on the bucket to apply a replication configuration</Message></Error>`))
+		case r.Method == http.MethodPut:
+			stored, _ = ioutil.ReadAll(r.Body)
+		case r.Method == http.MethodGet:
+			w.Write(stored)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	cfg := ReplicationConfig{
+		Role: "arn:aws:iam::123456789012:role/replication",
+		Rules: []ReplicationRule{{
+			ID:                "logs",
+			Priority:          1,
+			Status:            "Enabled",
+			Prefix:            "logs/",
+			DestinationBucket: "arn:aws:s3:::replica",
+			StorageClass:      StorageClassStandardIA,
+		}},
+	}
+	if err := s3.PutBucketReplication("bucket", cfg); err != nil {
+		t.Fatalf("S3.PutBucketReplication() error = %v", err)
+	}
+	want := `<ReplicationConfiguration><Role>arn:aws:iam::123456789012:role/replication</Role><Rule><ID>logs</ID><Priority>1</Priority><Status>Enabled</Status>` +
+		`<Filter><Prefix>logs/</Prefix></Filter><DeleteMarkerReplication><Status>Disabled</Status></DeleteMarkerReplication>` +
+		`<Destination><Bucket>arn:aws:s3:::replica</Bucket><StorageClass>STANDARD_IA</StorageClass></Destination></Rule></ReplicationConfiguration>`
+	if string(stored) != want {
+		t.Errorf("S3.PutBucketReplication() sent = %s, want %s", stored, want)
+	}
+
+	got, err := s3.GetBucketReplication("bucket")
+	if err != nil {
+		t.Fatalf("S3.GetBucketReplication() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("S3.GetBucketReplication() = %+v, want %+v", got, cfg)
+	}
+
+	if err := s3.PutBucketReplication("unversioned", cfg); !errors.Is(err, ErrVersioningNotEnabled) {
+		t.Errorf("S3.PutBucketReplication() error = %v, want ErrVersioningNotEnabled", err)
+	}
+}
@@ -0,0 +1,81 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+)
+
+// Retrieval tiers of RestoreInput, from the fastest
+// and most expensive to the slowest and cheapest.
+const (
+	RestoreTierExpedited = "Expedited"
+	RestoreTierStandard  = "Standard"
+	RestoreTierBulk      = "Bulk"
+)
+
+// Restore statuses returned by GetRestoreStatus.
+const (
+	RestoreStatusNone     = "none"
+	RestoreStatusOngoing  = "ongoing"
+	RestoreStatusComplete = "complete"
+)
+
+// RestoreInput is passed to RestoreObject as a parameter.
+type RestoreInput struct {
+	Bucket    string
+	ObjectKey string
+
+	// Days is how long the restored copy is kept.
+	Days int
+	// Tier is one of the RestoreTier constants,
+	// S3 uses Standard if it is empty.
+	Tier string
+}
+
+// restoreRequest is the XML representation of a RestoreInput.
+type restoreRequest struct {
+	XMLName              xml.Name              `xml:"RestoreRequest"`
+	Days                 int                   `xml:"Days"`
+	GlacierJobParameters *glacierJobParameters `xml:"GlacierJobParameters"`
+}
+
+// glacierJobParameters is omitted from the request
+// when no tier is set, as S3 rejects it empty.
+type glacierJobParameters struct {
+	Tier string `xml:"Tier"`
+}
+
+// RestoreObject makes a POST call to restore a temporary copy of an
+// object in the GLACIER or DEEP_ARCHIVE storage class, so that it can
+// be downloaded. Restoring takes from minutes to hours depending on
+// the tier, use GetRestoreStatus to know when it is done.
+func (s3 *S3) RestoreObject(in RestoreInput) error {
+	req := restoreRequest{Days: in.Days}
+	if in.Tier != "" {
+		req.GlacierJobParameters = &glacierJobParameters{Tier: in.Tier}
+	}
+	return s3.doXML(http.MethodPost, in.Bucket, in.ObjectKey, "?restore", nil, req, nil)
+}
+
+// GetRestoreStatus makes a HEAD call and returns RestoreStatusOngoing
+// while an object is being restored, RestoreStatusComplete once it can
+// be downloaded, or RestoreStatusNone if no restore was requested.
+func (s3 *S3) GetRestoreStatus(bucket, key string) (string, error) {
+	head, err := s3.FileHead(DownloadInput{Bucket: bucket, ObjectKey: key})
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case head.Restore == "":
+		return RestoreStatusNone, nil
+	case strings.Contains(head.Restore, `ongoing-request="true"`):
+		return RestoreStatusOngoing, nil
+	default:
+		return RestoreStatusComplete, nil
+	}
+}
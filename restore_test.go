@@ -0,0 +1,66 @@
+package gos3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestS3_RestoreObject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["restore"]; !ok || r.Method != http.MethodPost {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		want := `<RestoreRequest><Days>7</Days><GlacierJobParameters><Tier>Bulk</Tier></GlacierJobParameters></RestoreRequest>`
+		if r.URL.Path == "/bucket/default.tar" {
+			want = `<RestoreRequest><Days>1</Days></RestoreRequest>`
+		}
+		if string(body) != want {
+			t.Errorf("body = %s, want %s", body, want)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	err := s3.RestoreObject(RestoreInput{Bucket: "bucket", ObjectKey: "archive.tar", Days: 7, Tier: RestoreTierBulk})
+	if err != nil {
+		t.Errorf("S3.RestoreObject() error = %v", err)
+	}
+	if err := s3.RestoreObject(RestoreInput{Bucket: "bucket", ObjectKey: "default.tar", Days: 1}); err != nil {
+		t.Errorf("S3.RestoreObject() error = %v", err)
+	}
+}
+
+func TestS3_GetRestoreStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bucket/ongoing":
+			w.Header().Set("x-amz-restore", `ongoing-request="true"`)
+		case "/bucket/complete":
+			w.Header().Set("x-amz-restore", `ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"`)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	for key, want := range map[string]string{
+		"ongoing":  RestoreStatusOngoing,
+		"complete": RestoreStatusComplete,
+		"standard": RestoreStatusNone,
+	} {
+		got, err := s3.GetRestoreStatus("bucket", key)
+		if err != nil {
+			t.Errorf("S3.GetRestoreStatus(%s) error = %v", key, err)
+		}
+		if got != want {
+			t.Errorf("S3.GetRestoreStatus(%s) = %s, want %s", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 20 * time.Second
+)
+
+// RetryPolicy decides whether a failed request should be sent again.
+// attempt is the number of attempts made so far, starting at 1.
+// err is the error returned by the http client, if any, and
+// statusCode is the status of the response, or 0 if there is none.
+type RetryPolicy interface {
+	ShouldRetry(attempt int, err error, statusCode int) bool
+	Delay(attempt int) time.Duration
+}
+
+type defaultRetryPolicy struct {
+	maxAttempts int
+}
+
+// DefaultRetryPolicy returns a RetryPolicy which makes up to maxAttempts
+// attempts in total. It retries network errors and 429, 500 and 503
+// responses, waiting with exponential backoff and full jitter in between.
+func DefaultRetryPolicy(maxAttempts int) RetryPolicy {
+	return defaultRetryPolicy{maxAttempts: maxAttempts}
+}
+
+func (p defaultRetryPolicy) ShouldRetry(attempt int, err error, statusCode int) bool {
+	if attempt >= p.maxAttempts {
+		return false
+	}
+	if err != nil {
+		// The caller gave up, there is no point in trying again.
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+func (p defaultRetryPolicy) Delay(attempt int) time.Duration {
+	d := defaultRetryMaxDelay
+	if attempt < 16 {
+		if backoff := defaultRetryBaseDelay << uint(attempt-1); backoff < d {
+			d = backoff
+		}
+	}
+	return time.Duration(rand.Int63n(int64(d)))
+}
+
+// SetRetryPolicy can be used to retry requests failing with
+// transient errors. If the policy passed is nil, requests
+// are not retried, which is the default.
+func (s3 *S3) SetRetryPolicy(p RetryPolicy) *S3 {
+	s3.retryPolicy = p
+	return s3
+}
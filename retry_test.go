@@ -0,0 +1,72 @@
+package gos3
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestS3_RetryPolicy(t *testing.T) {
+	var attempts int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "hello world" {
+			t.Errorf("attempt %d: got body '%s'", attempts, body)
+		}
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+	s3.SetRetryPolicy(DefaultRetryPolicy(3))
+
+	_, err := s3.FilePut(UploadInput{
+		Bucket:    "bucket",
+		ObjectKey: "test.txt",
+		Body:      bytes.NewReader([]byte("hello world")),
+	})
+	if err != nil {
+		t.Fatalf("S3.FilePut() error = %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("S3.FilePut() made %d attempts, want 3", attempts)
+	}
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	p := DefaultRetryPolicy(3)
+	tests := []struct {
+		name       string
+		attempt    int
+		err        error
+		statusCode int
+		want       bool
+	}{
+		{"network error", 1, errors.New("connection reset"), 0, true},
+		{"throttled", 1, nil, http.StatusTooManyRequests, true},
+		{"internal error", 2, nil, http.StatusInternalServerError, true},
+		{"unavailable", 1, nil, http.StatusServiceUnavailable, true},
+		{"not found", 1, nil, http.StatusNotFound, false},
+		{"ok", 1, nil, http.StatusOK, false},
+		{"max attempts", 3, nil, http.StatusServiceUnavailable, false},
+	}
+	for _, tt := range tests {
+		if got := p.ShouldRetry(tt.attempt, tt.err, tt.statusCode); got != tt.want {
+			t.Errorf("%s: ShouldRetry() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	for attempt := 1; attempt < 64; attempt++ {
+		if d := p.Delay(attempt); d < 0 || d > defaultRetryMaxDelay {
+			t.Errorf("Delay(%d) = %v, out of range", attempt, d)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+)
+
+// Formats of SelectInput. Parquet is only
+// supported as an input format.
+const (
+	SelectFormatCSV     = "CSV"
+	SelectFormatJSON    = "JSON"
+	SelectFormatParquet = "Parquet"
+)
+
+// SelectInput is passed to SelectObject as a parameter.
+type SelectInput struct {
+	Bucket string
+	Key    string
+	// SQL is the query, eg. SELECT s.name FROM S3Object s.
+	SQL string
+
+	// InputFormat is the format of the object, one of the
+	// SelectFormat constants. JSON objects must hold one
+	// document per line.
+	InputFormat string
+	// OutputFormat is the format of the records,
+	// SelectFormatCSV or SelectFormatJSON.
+	OutputFormat string
+
+	// CSVHeader makes the first line of a CSV object name the
+	// columns, so that they can be used in the query.
+	CSVHeader bool
+}
+
+// selectRequest is the XML representation of a SelectInput.
+type selectRequest struct {
+	XMLName             xml.Name            `xml:"SelectObjectContentRequest"`
+	Expression          string              `xml:"Expression"`
+	ExpressionType      string              `xml:"ExpressionType"`
+	InputSerialization  inputSerialization  `xml:"InputSerialization"`
+	OutputSerialization outputSerialization `xml:"OutputSerialization"`
+}
+
+type inputSerialization struct {
+	CSV *struct {
+		FileHeaderInfo string `xml:"FileHeaderInfo"`
+	} `xml:"CSV"`
+	JSON *struct {
+		Type string `xml:"Type"`
+	} `xml:"JSON"`
+	Parquet *struct{} `xml:"Parquet"`
+}
+
+type outputSerialization struct {
+	CSV  *struct{} `xml:"CSV"`
+	JSON *struct{} `xml:"JSON"`
+}
+
+// selectRequest returns the XML representation of the input.
+func (in SelectInput) selectRequest() (selectRequest, error) {
+	req := selectRequest{Expression: in.SQL, ExpressionType: "SQL"}
+
+	switch in.InputFormat {
+	case SelectFormatCSV:
+		req.InputSerialization.CSV = &struct {
+			FileHeaderInfo string `xml:"FileHeaderInfo"`
+		}{"NONE"}
+		if in.CSVHeader {
+			req.InputSerialization.CSV.FileHeaderInfo = "USE"
+		}
+	case SelectFormatJSON:
+		req.InputSerialization.JSON = &struct {
+			Type string `xml:"Type"`
+		}{"LINES"}
+	case SelectFormatParquet:
+		req.InputSerialization.Parquet = &struct{}{}
+	default:
+		return selectRequest{}, fmt.Errorf("unknown input format %q", in.InputFormat)
+	}
+
+	switch in.OutputFormat {
+	case SelectFormatCSV:
+		req.OutputSerialization.CSV = &struct{}{}
+	case SelectFormatJSON:
+		req.OutputSerialization.JSON = &struct{}{}
+	default:
+		return selectRequest{}, fmt.Errorf("unknown output format %q", in.OutputFormat)
+	}
+	return req, nil
+}
+
+// SelectObject runs an S3 Select query on an object and returns the
+// matching records, without downloading the whole object. The records
+// are read from the event stream of the response as they arrive, an
+// error sent by S3 in the middle of the stream is returned by Read
+// as an *S3Error. After reading the records, ensure closing the reader.
+func (s3 *S3) SelectObject(in SelectInput) (io.ReadCloser, error) {
+	sel, err := in.selectRequest()
+	if err != nil {
+		return nil, err
+	}
+	body, err := xml.Marshal(sel)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s3.newRequest(
+		context.Background(), http.MethodPost, in.Bucket, in.Key, "?select&select-type=2", bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml")
+
+	if err := s3.signRequestWithBody(req, body); err != nil {
+		return nil, err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != 200 {
+		defer res.Body.Close()
+		return nil, newResponseError(res)
+	}
+	return &selectReader{body: res.Body}, nil
+}
+
+// selectReader reads the payload of the Records events of
+// an event stream, until the End event.
+// (https://docs.aws.amazon.com/AmazonS3/latest/API/RESTSelectObjectAppendix.html)
+type selectReader struct {
+	body    io.ReadCloser
+	records []byte
+	err     error
+}
+
+func (r *selectReader) Read(p []byte) (int, error) {
+	for len(r.records) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.records, r.err = r.nextRecords()
+	}
+	n := copy(p, r.records)
+	r.records = r.records[n:]
+	return n, nil
+}
+
+func (r *selectReader) Close() error {
+	return r.body.Close()
+}
+
+// nextRecords reads messages until the next Records event and returns
+// its payload. io.EOF is returned after the End event.
+func (r *selectReader) nextRecords() ([]byte, error) {
+	for {
+		headers, payload, err := readEventMessage(r.body)
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case headers[":message-type"] == "error":
+			return nil, &S3Error{
+				StatusCode: http.StatusOK,
+				Code:       headers[":error-code"],
+				Message:    headers[":error-message"],
+			}
+		case headers[":event-type"] == "Records":
+			return payload, nil
+		case headers[":event-type"] == "End":
+			return nil, io.EOF
+		}
+		// Stats, Progress and Cont events are skipped.
+	}
+}
+
+// readEventMessage reads a message of an event stream and
+// returns its string headers and its payload.
+func readEventMessage(r io.Reader) (map[string]string, []byte, error) {
+	// total length, headers length and CRC of both
+	var prelude [12]byte
+	if _, err := io.ReadFull(r, prelude[:]); err != nil {
+		return nil, nil, err
+	}
+	totalLength := binary.BigEndian.Uint32(prelude[0:4])
+	headersLength := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return nil, nil, errors.New("event stream: prelude checksum mismatch")
+	}
+	if totalLength < 16 || headersLength > totalLength-16 {
+		return nil, nil, fmt.Errorf("event stream: invalid message length %d", totalLength)
+	}
+
+	msg := make([]byte, totalLength)
+	copy(msg, prelude[:])
+	if _, err := io.ReadFull(r, msg[12:]); err != nil {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	end := len(msg) - 4
+	if crc32.ChecksumIEEE(msg[:end]) != binary.BigEndian.Uint32(msg[end:]) {
+		return nil, nil, errors.New("event stream: message checksum mismatch")
+	}
+
+	headers, err := parseEventHeaders(msg[12 : 12+headersLength])
+	if err != nil {
+		return nil, nil, err
+	}
+	return headers, msg[12+headersLength : end], nil
+}
+
+// parseEventHeaders parses the headers of an event stream message,
+// all of which are strings for S3 Select.
+func parseEventHeaders(b []byte) (map[string]string, error) {
+	headers := map[string]string{}
+	for len(b) > 0 {
+		nameLength := int(b[0])
+		if len(b) < 1+nameLength+3 {
+			return nil, errors.New("event stream: truncated header")
+		}
+		name := string(b[1 : 1+nameLength])
+		b = b[1+nameLength:]
+
+		// Only string values, of type 7, are expected.
+		if b[0] != 7 {
+			return nil, fmt.Errorf("event stream: header %s has unsupported type %d", name, b[0])
+		}
+		valueLength := int(binary.BigEndian.Uint16(b[1:3]))
+		if len(b) < 3+valueLength {
+			return nil, errors.New("event stream: truncated header")
+		}
+		headers[name] = string(b[3 : 3+valueLength])
+		b = b[3+valueLength:]
+	}
+	return headers, nil
+}
@@ -0,0 +1,118 @@
+package gos3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// eventMessage encodes a message of an event stream
+// with string headers.
+func eventMessage(headers map[string]string, payload string) []byte {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var h bytes.Buffer
+	for _, name := range names {
+		h.WriteByte(byte(len(name)))
+		h.WriteString(name)
+		h.WriteByte(7)
+		binary.Write(&h, binary.BigEndian, uint16(len(headers[name])))
+		h.WriteString(headers[name])
+	}
+
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, uint32(16+h.Len()+len(payload)))
+	binary.Write(&b, binary.BigEndian, uint32(h.Len()))
+	binary.Write(&b, binary.BigEndian, crc32.ChecksumIEEE(b.Bytes()))
+	b.Write(h.Bytes())
+	b.WriteString(payload)
+	binary.Write(&b, binary.BigEndian, crc32.ChecksumIEEE(b.Bytes()))
+	return b.Bytes()
+}
+
+func recordsEvent(payload string) []byte {
+	return eventMessage(map[string]string{":message-type": "event", ":event-type": "Records"}, payload)
+}
+
+func TestS3_SelectObject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if _, ok := q["select"]; !ok || q.Get("select-type") != "2" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		want := `<SelectObjectContentRequest><Expression>SELECT s.name FROM S3Object s</Expression><ExpressionType>SQL</ExpressionType>` +
+			`<InputSerialization><CSV><FileHeaderInfo>USE</FileHeaderInfo></CSV></InputSerialization>` +
+			`<OutputSerialization><JSON></JSON></OutputSerialization></SelectObjectContentRequest>`
+		if string(body) != want {
+			t.Errorf("body = %s, want %s", body, want)
+		}
+
+		if r.URL.Path == "/bucket/broken.csv" {
+			w.Write(recordsEvent(`{"name":"a"}` + "\n"))
+			w.Write(eventMessage(map[string]string{
+				":message-type":  "error",
+				":error-code":    "CSVParsingError",
+				":error-message": "Encountered an error parsing the CSV file.",
+			}, ""))
+			return
+		}
+		w.Write(recordsEvent(`{"name":"a"}` + "\n"))
+		w.Write(eventMessage(map[string]string{":message-type": "event", ":event-type": "Stats"}, "<Stats/>"))
+		w.Write(recordsEvent(`{"name":"b"}` + "\n"))
+		w.Write(eventMessage(map[string]string{":message-type": "event", ":event-type": "End"}, ""))
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	in := SelectInput{
+		Bucket:       "bucket",
+		Key:          "people.csv",
+		SQL:          "SELECT s.name FROM S3Object s",
+		InputFormat:  SelectFormatCSV,
+		OutputFormat: SelectFormatJSON,
+		CSVHeader:    true,
+	}
+	records, err := s3.SelectObject(in)
+	if err != nil {
+		t.Fatalf("S3.SelectObject() error = %v", err)
+	}
+	got, err := ioutil.ReadAll(records)
+	records.Close()
+	if err != nil {
+		t.Fatalf("reading records error = %v", err)
+	}
+	if want := "{\"name\":\"a\"}\n{\"name\":\"b\"}\n"; string(got) != want {
+		t.Errorf("S3.SelectObject() records = %q, want %q", got, want)
+	}
+
+	in.Key = "broken.csv"
+	records, err = s3.SelectObject(in)
+	if err != nil {
+		t.Fatalf("S3.SelectObject() error = %v", err)
+	}
+	_, err = ioutil.ReadAll(records)
+	records.Close()
+	var s3Err *S3Error
+	if !errors.As(err, &s3Err) || s3Err.Code != "CSVParsingError" {
+		t.Errorf("reading records error = %v, want CSVParsingError", err)
+	}
+
+	in.OutputFormat = SelectFormatParquet
+	if _, err := s3.SelectObject(in); err == nil || !strings.Contains(err.Error(), "output format") {
+		t.Errorf("S3.SelectObject() error = %v, want an output format error", err)
+	}
+}
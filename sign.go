@@ -13,16 +13,15 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 )
 
-func (s3 *S3) signKeys(t time.Time) []byte {
+func (s3 *S3) signKeys(t time.Time, region, service string) []byte {
 	h := makeHMac([]byte("AWS4"+s3.SecretKey), []byte(t.Format(shortTimeFormat)))
-	h = makeHMac(h, []byte(s3.Region))
-	h = makeHMac(h, []byte(serviceName))
+	h = makeHMac(h, []byte(region))
+	h = makeHMac(h, []byte(service))
 	h = makeHMac(h, []byte("aws4_request"))
 	return h
 }
@@ -44,13 +43,13 @@ func (s3 *S3) writeRequest(w io.Writer, r *http.Request) {
 	writeBody(w, r)
 }
 
-func (s3 *S3) writeStringToSign(w io.Writer, t time.Time, r *http.Request) {
+func (s3 *S3) writeStringToSign(w io.Writer, t time.Time, scope string, r *http.Request) {
 	w.Write([]byte(algorithm))
 	w.Write(newLine)
 	w.Write([]byte(t.Format(amzDateISO8601TimeFormat)))
 	w.Write(newLine)
 
-	w.Write([]byte(s3.creds(t)))
+	w.Write([]byte(scope))
 	w.Write(newLine)
 
 	h := sha256.New()
@@ -58,34 +57,29 @@ func (s3 *S3) writeStringToSign(w io.Writer, t time.Time, r *http.Request) {
 	fmt.Fprintf(w, "%x", h.Sum(nil))
 }
 
-func (s3 *S3) creds(t time.Time) string {
-	return t.Format(shortTimeFormat) + "/" + s3.Region + "/" + serviceName + "/aws4_request"
+func (s3 *S3) creds(t time.Time, region, service string) string {
+	return t.Format(shortTimeFormat) + "/" + region + "/" + service + "/aws4_request"
 }
 
+// writeURI writes the path of the request as it is sent. Unlike other
+// services, S3 does not normalize it, so that keys with leading or
+// repeated slashes are signed as is.
 func writeURI(w io.Writer, r *http.Request) {
 	path := r.URL.RequestURI()
 	if r.URL.RawQuery != "" {
 		path = path[:len(path)-len(r.URL.RawQuery)-1]
 	}
-	slash := strings.HasSuffix(path, "/")
-	path = filepath.Clean(path)
-	if path != "/" && slash {
-		path += "/"
-	}
 	w.Write([]byte(path))
 }
 
 func writeQuery(w io.Writer, r *http.Request) {
 	var a []string
 	for k, vs := range r.URL.Query() {
-		k = url.QueryEscape(k)
+		k = uriEncode(k)
 		for _, v := range vs {
-			if v == "" {
-				a = append(a, k)
-			} else {
-				v = url.QueryEscape(v)
-				a = append(a, k+"="+v)
-			}
+			// Sub-resources such as ?uploads have no value, but
+			// still need the trailing '=' in the canonical form.
+			a = append(a, k+"="+uriEncode(v))
 		}
 	}
 	sort.Strings(a)
@@ -97,6 +91,12 @@ func writeQuery(w io.Writer, r *http.Request) {
 	}
 }
 
+// uriEncode escapes s as required by the canonical request,
+// where spaces are encoded as %20 rather than '+'.
+func uriEncode(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
 func writeHeader(w io.Writer, r *http.Request) {
 	i, a := 0, make([]string, len(r.Header))
 	for k, v := range r.Header {
@@ -133,6 +133,13 @@ func writeBody(w io.Writer, r *http.Request) {
 		b   []byte
 		err error
 	)
+	// S3 expects the canonical request to carry the same payload
+	// hash that was sent in the x-amz-content-sha256 header.
+	if hash := r.Header.Get("x-amz-content-sha256"); hash != "" {
+		io.WriteString(w, hash)
+		return
+	}
+
 	// If the payload is empty, use the empty string as the input to the SHA256 function
 	// http://docs.amazonwebservices.com/general/latest/gr/sigv4-create-canonical-request.html
 	if r.Body == nil {
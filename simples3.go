@@ -5,8 +5,12 @@ package gos3
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
+	"crypto/md5"
 	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
@@ -15,16 +19,65 @@ import (
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
 	securityCredentialsURL = "http://169.254.169.254/latest/meta-data/iam/security-credentials/"
+
+	amzMetaPrefix = "x-amz-meta-"
+	// maxMetadataSize is the maximum size of the
+	// user-defined metadata of an object.
+	maxMetadataSize = 2 * 1024
+
+	defaultURIFormat = "https://s3.%s.amazonaws.com/%s"
+)
+
+// URLStyle determines how the bucket is addressed in request URLs.
+type URLStyle int
+
+const (
+	// PathStyle puts the bucket in the path of the URL,
+	// eg. https://s3.<region>.amazonaws.com/<bucket>/<key>.
+	PathStyle URLStyle = iota
+	// VirtualHostedStyle puts the bucket in the host of the URL,
+	// eg. https://<bucket>.s3.<region>.amazonaws.com/<key>.
+	VirtualHostedStyle
+)
+
+// SSEType is the server-side encryption applied to an uploaded object.
+type SSEType string
+
+const (
+	// SSENone leaves the encryption to the default of the bucket.
+	SSENone SSEType = ""
+	// SSES3 encrypts the object with keys managed by S3.
+	SSES3 SSEType = "AES256"
+	// SSEKMS encrypts the object with a key managed by AWS KMS.
+	SSEKMS SSEType = "aws:kms"
+)
+
+// Storage classes accepted by UploadInput.StorageClass.
+const (
+	StorageClassStandard           = "STANDARD"
+	StorageClassReducedRedundancy  = "REDUCED_REDUNDANCY"
+	StorageClassStandardIA         = "STANDARD_IA"
+	StorageClassOneZoneIA          = "ONEZONE_IA"
+	StorageClassIntelligentTiering = "INTELLIGENT_TIERING"
+	StorageClassGlacier            = "GLACIER"
+	StorageClassGlacierIR          = "GLACIER_IR"
+	StorageClassDeepArchive        = "DEEP_ARCHIVE"
 )
 
 // S3 provides a wrapper around your S3 credentials.
 type S3 struct {
+	// mu guards AccessKey, SecretKey and Token, which
+	// may be updated by a background credential refresher.
+	mu sync.RWMutex
+
 	AccessKey string
 	SecretKey string
 	Region    string
@@ -33,12 +86,103 @@ type S3 struct {
 	Token     string
 	Endpoint  string
 	URIFormat string
+	URLStyle  URLStyle
+
+	// RequesterPays is set by SetRequesterPays.
+	RequesterPays bool
+
+	// accelerate, dualStack and fips are set by
+	// UseAccelerate, UseDualStack and UseFIPS.
+	accelerate bool
+	dualStack  bool
+	fips       bool
+
+	urlBuilder URLBuilder
+
+	retryPolicy  RetryPolicy
+	logger       Logger
+	metadataFunc func(*http.Request, RequestMetadata)
+
+	// wrappedMu guards the middlewares, wrapped, the client
+	// wrapped in them, and wrappedFrom, the client it was built from.
+	wrappedMu   sync.Mutex
+	middlewares []Middleware
+	wrapped     *http.Client
+	wrappedFrom *http.Client
 }
 
 // DownloadInput is passed to FileUpload as a parameter.
 type DownloadInput struct {
 	Bucket    string
 	ObjectKey string
+
+	// VersionID selects a specific version of the object
+	// in a versioned bucket, instead of the latest one.
+	VersionID string
+
+	// ExpectedMD5 is the hex-encoded MD5 of the object,
+	// checked by FileDownloadVerified if set.
+	ExpectedMD5 string
+
+	// IfNoneMatch and IfModifiedSince make the download conditional,
+	// for clients caching objects. If the object has the ETag
+	// IfNoneMatch or is unchanged since IfModifiedSince, the
+	// returned error matches ErrNotModified.
+	IfNoneMatch     string
+	IfModifiedSince *time.Time
+
+	// SSECKey is the 32 bytes AES-256 key the object
+	// was uploaded with using UploadInput.SSECKey.
+	SSECKey []byte
+
+	// VerifyChecksum asks S3 for the checksum the object was
+	// uploaded with using UploadInput.Checksum, and checks the
+	// body against it as it is read. Reading the end of a body
+	// which differs returns ErrChecksumMismatch. Objects without
+	// a checksum, or uploaded in multiple parts, are unverified.
+	VerifyChecksum bool
+}
+
+// setConditionalHeaders sets the headers of a conditional GET.
+func (u DownloadInput) setConditionalHeaders(h http.Header) {
+	if u.IfNoneMatch != "" {
+		h.Set("If-None-Match", u.IfNoneMatch)
+	}
+	if u.IfModifiedSince != nil {
+		h.Set("If-Modified-Since", u.IfModifiedSince.UTC().Format(http.TimeFormat))
+	}
+}
+
+// setSSECHeaders sets the headers of a request
+// for an object encrypted with an SSE-C key.
+func setSSECHeaders(h http.Header, key []byte) {
+	for k, v := range sseCustomerFields(key) {
+		h.Set(k, v)
+	}
+}
+
+// sseCustomerFields returns the fields sent along with
+// an SSE-C key, which are empty if key is nil.
+func sseCustomerFields(key []byte) map[string]string {
+	fields := map[string]string{}
+	if key == nil {
+		return fields
+	}
+	sum := md5.Sum(key)
+	fields["x-amz-server-side-encryption-customer-algorithm"] = "AES256"
+	fields["x-amz-server-side-encryption-customer-key"] = base64.StdEncoding.EncodeToString(key)
+	fields["x-amz-server-side-encryption-customer-key-MD5"] = base64.StdEncoding.EncodeToString(sum[:])
+	return fields
+}
+
+// DownloadRangeInput is passed to FileDownloadRange as a parameter.
+// Start and End are inclusive byte offsets, if End is zero
+// the range extends to the end of the object.
+type DownloadRangeInput struct {
+	DownloadInput
+
+	Start int64
+	End   int64
 }
 
 // UploadInput is passed to FileUpload as a parameter.
@@ -53,6 +197,53 @@ type UploadInput struct {
 	ContentDisposition string
 	ACL                string
 
+	// CacheControl and Expires are served along with the object,
+	// eg. to set the TTL of CDN and browser caches. Expires is
+	// only sent if not zero.
+	CacheControl string
+	Expires      time.Time
+
+	// WebsiteRedirectLocation makes a bucket configured as a static
+	// website redirect requests for the object to another object
+	// of the bucket or to an external URL.
+	WebsiteRedirectLocation string
+
+	// Metadata is stored along with the object as x-amz-meta-* headers.
+	// Keys are lower-cased, and keys and values may not exceed 2 KB in total.
+	Metadata map[string]string
+
+	// Encryption selects server-side encryption of the object.
+	// KMSKeyID is the KMS key to use with SSEKMS, if not set
+	// the AWS managed key is used.
+	Encryption SSEType
+	KMSKeyID   string
+
+	// SSECKey encrypts the object with a 32 bytes AES-256 key
+	// managed by the caller (SSE-C) rather than by AWS. S3 does
+	// not store the key, the same key must be passed to download
+	// the object. It cannot be combined with Encryption.
+	SSECKey []byte
+
+	// StorageClass is one of the StorageClass constants,
+	// if not set S3 uses STANDARD.
+	StorageClass string
+
+	// VerifyMD5 makes FilePut send the MD5 of the body, which
+	// S3 checks before storing the object. It requires reading
+	// the body twice.
+	VerifyMD5 bool
+
+	// Checksum is one of the Checksum constants. If set, FilePut
+	// sends the checksum of the body, and multipart uploads that of
+	// every part, which S3 checks and stores along with the object.
+	// It requires reading the body twice. FileUpload does not
+	// support it and returns an error.
+	Checksum string
+
+	// ProgressFunc, if set, is called as the body is read
+	// with the number of bytes read so far and the total size.
+	ProgressFunc func(bytesWritten, totalBytes int64)
+
 	Body io.ReadSeeker
 }
 
@@ -70,12 +261,37 @@ type UploadResponse struct {
 	Bucket   string `xml:"Bucket"`
 	Key      string `xml:"Key"`
 	ETag     string `xml:"ETag"`
+
+	// MD5 is the base64-encoded MD5 of the body sent
+	// by FilePut when UploadInput.VerifyMD5 is set.
+	MD5 string `xml:"-"`
+}
+
+// HeadOutput is returned by FileHead and contains
+// the metadata of an object.
+type HeadOutput struct {
+	ContentLength int64
+	ContentType   string
+	LastModified  time.Time
+	ETag          string
+
+	// Metadata contains the user-defined x-amz-meta-* headers,
+	// keyed by the lower-cased name without the prefix.
+	Metadata map[string]string
+
+	// Restore is the raw x-amz-restore header of archived
+	// objects, see GetRestoreStatus.
+	Restore string
 }
 
 // DeleteInput is passed to FileDelete as a parameter.
 type DeleteInput struct {
 	Bucket    string
 	ObjectKey string
+
+	// VersionID permanently deletes a specific version of the
+	// object, instead of adding a delete marker.
+	VersionID string
 }
 
 // IAMResponse is used by NewUsingIAM to auto
@@ -90,15 +306,118 @@ type IAMResponse struct {
 	Expiration      string `json:"Expiration"`
 }
 
-// New returns an instance of S3.
-func New(region, accessKey, secretKey string) *S3 {
-	return &S3{
-		Region:    region,
-		AccessKey: accessKey,
-		SecretKey: secretKey,
+// validate checks the optional fields of the input
+// before any request is made.
+func (u UploadInput) validate() error {
+	size := 0
+	for k, v := range u.Metadata {
+		size += len(k) + len(v)
+	}
+	if size > maxMetadataSize {
+		return ErrMetadataTooLarge
+	}
 
-		URIFormat: "https://s3.%s.amazonaws.com/%s",
+	switch u.Encryption {
+	case SSENone, SSES3, SSEKMS:
+	default:
+		return fmt.Errorf("unknown encryption %q", u.Encryption)
+	}
+	if u.KMSKeyID != "" && u.Encryption != SSEKMS {
+		return errors.New("KMSKeyID requires SSEKMS encryption")
+	}
+	if u.SSECKey != nil {
+		if len(u.SSECKey) != 32 {
+			return fmt.Errorf("SSECKey is %d bytes long, want 32", len(u.SSECKey))
+		}
+		if u.Encryption != SSENone {
+			return errors.New("SSECKey cannot be combined with Encryption")
+		}
 	}
+
+	switch u.StorageClass {
+	case "",
+		StorageClassStandard,
+		StorageClassReducedRedundancy,
+		StorageClassStandardIA,
+		StorageClassOneZoneIA,
+		StorageClassIntelligentTiering,
+		StorageClassGlacier,
+		StorageClassGlacierIR,
+		StorageClassDeepArchive:
+	default:
+		return fmt.Errorf("unknown storage class %q", u.StorageClass)
+	}
+	if u.Checksum != "" {
+		if _, err := newChecksumHash(u.Checksum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setHeaders sets the headers describing the object
+// on a PUT request or on a multipart upload.
+func (u UploadInput) setHeaders(h http.Header) {
+	h.Set("Content-Type", u.ContentType)
+	if u.ContentDisposition != "" {
+		h.Set("Content-Disposition", u.ContentDisposition)
+	}
+	if u.ACL != "" {
+		h.Set("x-amz-acl", u.ACL)
+	}
+	for k, v := range u.cacheFields() {
+		h.Set(k, v)
+	}
+	for k, v := range u.amzFields() {
+		h.Set(k, v)
+	}
+}
+
+// cacheFields returns the caching fields of the input, which are
+// sent as headers, or as form fields of a POST upload.
+func (u UploadInput) cacheFields() map[string]string {
+	fields := map[string]string{}
+	if u.CacheControl != "" {
+		fields["Cache-Control"] = u.CacheControl
+	}
+	if !u.Expires.IsZero() {
+		fields["Expires"] = u.Expires.UTC().Format(http.TimeFormat)
+	}
+	return fields
+}
+
+// amzFields returns the x-amz-* fields of the input, which are
+// sent as headers, or as form fields of a POST upload.
+func (u UploadInput) amzFields() map[string]string {
+	fields := map[string]string{}
+	for k, v := range u.Metadata {
+		fields[amzMetaPrefix+strings.ToLower(k)] = v
+	}
+	if u.StorageClass != "" {
+		fields["x-amz-storage-class"] = u.StorageClass
+	}
+	if u.Encryption != SSENone {
+		fields["x-amz-server-side-encryption"] = string(u.Encryption)
+	}
+	if u.KMSKeyID != "" {
+		fields["x-amz-server-side-encryption-aws-kms-key-id"] = u.KMSKeyID
+	}
+	for k, v := range sseCustomerFields(u.SSECKey) {
+		fields[k] = v
+	}
+	if u.WebsiteRedirectLocation != "" {
+		fields["x-amz-website-redirect-location"] = u.WebsiteRedirectLocation
+	}
+	return fields
+}
+
+// New returns an instance of S3.
+func New(region, accessKey, secretKey string) *S3 {
+	return NewWithOptions(
+		WithRegion(region),
+		WithAccessKey(accessKey),
+		WithSecretKey(secretKey),
+	)
 }
 
 // NewUsingIAM automatically generates an Instance of S3
@@ -108,68 +427,222 @@ func NewUsingIAM(region string) (*S3, error) {
 }
 
 func newUsingIAMImpl(baseURL, region string) (*S3, error) {
-	// Get the IAM role
-	resp, err := http.Get(baseURL)
+	jsonResp, err := fetchIAMCredentials(baseURL)
 	if err != nil {
 		return nil, err
 	}
+
+	return &S3{
+		Region:    region,
+		AccessKey: jsonResp.AccessKeyID,
+		SecretKey: jsonResp.SecretAccessKey,
+		Token:     jsonResp.Token,
+
+		URIFormat: defaultURIFormat,
+	}, nil
+}
+
+// fetchIAMCredentials gets the credentials of the
+// IAM role attached to the instance.
+func fetchIAMCredentials(baseURL string) (IAMResponse, error) {
+	// Get the IAM role
+	resp, err := metadataClient.Get(baseURL)
+	if err != nil {
+		return IAMResponse{}, err
+	}
 	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		return nil, errors.New(http.StatusText(resp.StatusCode))
+		return IAMResponse{}, errors.New(http.StatusText(resp.StatusCode))
 	}
 
 	role, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return IAMResponse{}, err
 	}
 
-	resp, err = http.Get(baseURL + "/" + string(role))
+	resp, err = metadataClient.Get(baseURL + "/" + string(role))
 	if err != nil {
-		return nil, err
+		return IAMResponse{}, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		return nil, errors.New(http.StatusText(resp.StatusCode))
+		return IAMResponse{}, errors.New(http.StatusText(resp.StatusCode))
 	}
 
 	var jsonResp IAMResponse
 	jsonString, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return IAMResponse{}, err
 	}
 
 	if err := json.Unmarshal(jsonString, &jsonResp); err != nil {
-		return nil, err
+		return IAMResponse{}, err
 	}
+	return jsonResp, nil
+}
 
-	return &S3{
-		Region:    region,
-		AccessKey: jsonResp.AccessKeyID,
-		SecretKey: jsonResp.SecretAccessKey,
-		Token:     jsonResp.Token,
+func (s3 *S3) getClient() *http.Client {
+	client := s3.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
 
-		URIFormat: "https://s3.%s.amazonaws.com/%s",
-	}, nil
+	// The middlewares are only applied again once Use
+	// is called or the client is replaced.
+	s3.wrappedMu.Lock()
+	defer s3.wrappedMu.Unlock()
+	if len(s3.middlewares) == 0 {
+		return client
+	}
+	if s3.wrapped == nil || s3.wrappedFrom != client {
+		s3.wrapped, s3.wrappedFrom = wrapClient(client, s3.middlewares), client
+	}
+	return s3.wrapped
 }
 
-func (s3 *S3) getClient() *http.Client {
-	if s3.Client == nil {
-		return http.DefaultClient
+// do sends the request using the configured http client,
+// retrying it as long as the retry policy allows.
+// Requests with a body are only retried if it can be
+// obtained again through req.GetBody.
+func (s3 *S3) do(req *http.Request) (*http.Response, error) {
+	client := s3.getClient()
+	for attempt := 1; ; attempt++ {
+		if s3.logger != nil {
+			s3.logger.LogRequest(req)
+		}
+		res, err := client.Do(req)
+		if s3.logger != nil && res != nil {
+			s3.logger.LogResponse(res)
+		}
+		if s3.metadataFunc != nil && res != nil {
+			s3.metadataFunc(req, newRequestMetadata(res.Header))
+		}
+		if s3.retryPolicy == nil {
+			return res, err
+		}
+
+		statusCode := 0
+		if res != nil {
+			statusCode = res.StatusCode
+		}
+		if !s3.retryPolicy.ShouldRetry(attempt, err, statusCode) {
+			return res, err
+		}
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return res, err
+			}
+			body, berr := req.GetBody()
+			if berr != nil {
+				return res, err
+			}
+			req.Body = body
+		}
+		if res != nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		select {
+		case <-time.After(s3.retryPolicy.Delay(attempt)):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
 	}
-	return s3.Client
 }
 
-func (s3 *S3) getURL(bucket string, args ...string) (uri string) {
-	if len(s3.Endpoint) > 0 {
-		uri = s3.Endpoint + "/" + bucket
-	} else {
-		uri = fmt.Sprintf(s3.URIFormat, s3.Region, bucket)
+// doXML makes a signed call to the object key in bucket, or to the
+// bucket if key is empty, with query appended to the URL and in, if
+// not nil, marshaled as the XML body. If out is not nil, the XML
+// response is unmarshaled into it. Any 2xx status code is a success.
+func (s3 *S3) doXML(method, bucket, key, query string, header http.Header, in, out interface{}) error {
+	var (
+		body    []byte
+		reqBody io.Reader
+	)
+	if in != nil {
+		var err error
+		if body, err = xml.Marshal(in); err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := s3.newRequest(context.Background(), method, bucket, key, query, reqBody)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if in != nil {
+		// Several of the configuration APIs require the Content-MD5
+		// header, it does not hurt to send it for the others.
+		sum := md5.Sum(body)
+		req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+		req.Header.Set("Content-Type", "application/xml")
+	}
+
+	if err := s3.signRequestWithBody(req, body); err != nil {
+		return err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return newResponseError(res)
+	}
+	if out == nil {
+		return nil
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
 	}
+	return xml.Unmarshal(data, out)
+}
+
+// getURL returns the URL of the object whose key is args joined
+// by slashes in bucket, or of the bucket if args is empty. It is
+// empty if bucket is a malformed access point ARN.
+func (s3 *S3) getURL(bucket string, args ...string) string {
+	uri, _, _ := s3.resolve(bucket, strings.Join(args, "/"))
+	return uri
+}
 
-	if len(args) > 0 {
-		uri = uri + "/" + strings.Join(args, "/")
+// withRegion returns a copy of the client which
+// makes and signs its requests for region.
+func (s3 *S3) withRegion(region string) *S3 {
+	s3.mu.RLock()
+	defer s3.mu.RUnlock()
+	s3.wrappedMu.Lock()
+	middlewares := append([]Middleware(nil), s3.middlewares...)
+	s3.wrappedMu.Unlock()
+
+	return &S3{
+		AccessKey:     s3.AccessKey,
+		SecretKey:     s3.SecretKey,
+		Region:        region,
+		Client:        s3.Client,
+		Token:         s3.Token,
+		Endpoint:      s3.Endpoint,
+		URIFormat:     s3.URIFormat,
+		URLStyle:      s3.URLStyle,
+		RequesterPays: s3.RequesterPays,
+		accelerate:    s3.accelerate,
+		dualStack:     s3.dualStack,
+		fips:          s3.fips,
+		urlBuilder:    s3.urlBuilder,
+		retryPolicy:   s3.retryPolicy,
+		logger:        s3.logger,
+		metadataFunc:  s3.metadataFunc,
+		middlewares:   middlewares,
 	}
-	return
 }
 
 // SetEndpoint can be used to the set a custom endpoint for
@@ -185,11 +658,33 @@ func (s3 *S3) SetEndpoint(uri string) *S3 {
 	return s3
 }
 
+// SetURLStyle can be used to switch between path-style and
+// virtual-hosted-style URLs. The default is PathStyle.
+// Requests are signed for the host of the resulting URL,
+// so the style also applies to custom endpoints.
+func (s3 *S3) SetURLStyle(style URLStyle) *S3 {
+	s3.URLStyle = style
+	return s3
+}
+
+// SetRequesterPays can be used to access requester pays buckets,
+// whose data transfer and request costs are billed to the caller
+// rather than to the owner. When enabled, every request is sent
+// and signed with the x-amz-request-payer header, and presigned
+// URLs carry it in their query. POST policies, as used by
+// FileUpload and CreatePresignedPost, do not.
+func (s3 *S3) SetRequesterPays(enabled bool) *S3 {
+	s3.RequesterPays = enabled
+	return s3
+}
+
 // SetToken can be used to set a Temporary Security Credential token obtained from
 // using an IAM role or AWS STS.
 func (s3 *S3) SetToken(token string) *S3 {
 	if token != "" {
+		s3.mu.Lock()
 		s3.Token = token
+		s3.mu.Unlock()
 	}
 	return s3
 }
@@ -221,6 +716,35 @@ func (s3 *S3) SetClient(client *http.Client) *S3 {
 }
 
 func (s3 *S3) signRequest(req *http.Request) error {
+	region, service := s3.requestScope(req)
+	return s3.signRequestFor(req, region, service)
+}
+
+// signingScopeKey is the context key of the signingScope of a request.
+type signingScopeKey struct{}
+
+// signingScope is the region and service for which the requests
+// to an endpoint are signed, when they differ from the client's.
+type signingScope struct {
+	region, service string
+}
+
+// requestScope returns the region and service for which req is
+// signed: those carried by its context if it was made by newRequest
+// for an access point, otherwise those of the client.
+func (s3 *S3) requestScope(req *http.Request) (region, service string) {
+	if scope, ok := req.Context().Value(signingScopeKey{}).(signingScope); ok {
+		return scope.region, scope.service
+	}
+	return s3.Region, serviceName
+}
+
+// signRequestFor signs req for region and service, for requests
+// to other services than S3 or to other regions than the client's.
+func (s3 *S3) signRequestFor(req *http.Request, region, service string) error {
+	s3.mu.RLock()
+	defer s3.mu.RUnlock()
+
 	var (
 		err error
 
@@ -240,16 +764,29 @@ func (s3 *S3) signRequest(req *http.Request) error {
 	// Signature Version 4 requests. It provides a hash of the
 	// request payload. If there is no payload, you must provide
 	// the hash of an empty string.
-	emptyhash := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
-	req.Header.Set("x-amz-content-sha256", emptyhash)
+	// Callers streaming a body may set it to UNSIGNED-PAYLOAD or
+	// STREAMING-AWS4-HMAC-SHA256-PAYLOAD beforehand, others use
+	// signRequestWithBody to sign the hash of the body.
+	if req.Header.Get("x-amz-content-sha256") == "" {
+		req.Header.Set("x-amz-content-sha256", emptyHash)
+	}
+
+	// Temporary credentials are only valid along with their token.
+	if s3.Token != "" {
+		req.Header.Set("x-amz-security-token", s3.Token)
+	}
+	if s3.RequesterPays {
+		req.Header.Set("x-amz-request-payer", "requester")
+	}
 
-	k := s3.signKeys(t)
+	scope := s3.creds(t, region, service)
+	k := s3.signKeys(t, region, service)
 	h := hmac.New(sha256.New, k)
 
-	s3.writeStringToSign(h, t, req)
+	s3.writeStringToSign(h, t, scope, req)
 
 	auth := bytes.NewBufferString(algorithm)
-	auth.Write([]byte(" Credential=" + s3.AccessKey + "/" + s3.creds(t)))
+	auth.Write([]byte(" Credential=" + s3.AccessKey + "/" + scope))
 	auth.Write([]byte{',', ' '})
 	auth.Write([]byte("SignedHeaders="))
 	writeHeaderList(auth, req)
@@ -260,50 +797,326 @@ func (s3 *S3) signRequest(req *http.Request) error {
 	return nil
 }
 
+// signRequestWithBody is like signRequest but includes the SHA256
+// hash of body in the signature, so that S3 rejects the request if
+// the body is modified in transit. Bodies which are streamed rather
+// than held in memory are sent as UNSIGNED-PAYLOAD instead.
+func (s3 *S3) signRequestWithBody(req *http.Request, body []byte) error {
+	sum := sha256.Sum256(body)
+	req.Header.Set("x-amz-content-sha256", hex.EncodeToString(sum[:]))
+	return s3.signRequest(req)
+}
+
 // FileDownload makes a GET call and returns a io.ReadCloser.
 // After reading the response body, ensure closing the response.
+// If u makes the call conditional and the object is unchanged,
+// the returned error matches ErrNotModified.
 func (s3 *S3) FileDownload(u DownloadInput) (io.ReadCloser, error) {
-	req, err := http.NewRequest(
-		http.MethodGet, s3.getURL(u.Bucket, u.ObjectKey), nil,
+	return s3.FileDownloadWithContext(context.Background(), u)
+}
+
+// FileDownloadWithContext is like FileDownload but the request is
+// bound to ctx. If ctx is canceled before the response is received,
+// the returned error wraps ctx.Err().
+func (s3 *S3) FileDownloadWithContext(ctx context.Context, u DownloadInput) (io.ReadCloser, error) {
+	res, err := s3.download(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// FileDownloadWithProgress is like FileDownload but progress, if not
+// nil, is called as the body is read with the number of bytes read so
+// far and the size of the object, which is -1 if S3 did not send it.
+func (s3 *S3) FileDownloadWithProgress(u DownloadInput, progress func(downloaded, total int64)) (io.ReadCloser, error) {
+	res, err := s3.download(context.Background(), u)
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{withProgress(res.Body, res.ContentLength, progress), res.Body}, nil
+}
+
+// download makes the GET call of FileDownload
+// and returns the successful response.
+func (s3 *S3) download(ctx context.Context, u DownloadInput) (*http.Response, error) {
+	if err := ValidateObjectKey(u.ObjectKey); err != nil {
+		return nil, err
+	}
+	req, err := s3.newRequest(
+		ctx, http.MethodGet, u.Bucket, u.ObjectKey, versionQuery(u.VersionID), nil,
 	)
 	if err != nil {
 		return nil, err
 	}
+	u.setConditionalHeaders(req.Header)
+	setSSECHeaders(req.Header, u.SSECKey)
+	if u.VerifyChecksum {
+		req.Header.Set("x-amz-checksum-mode", "ENABLED")
+	}
 
 	if err := s3.signRequest(req); err != nil {
 		return nil, err
 	}
 
-	res, err := s3.getClient().Do(req)
+	res, err := s3.do(req)
 	if err != nil {
 		return nil, err
 	}
 
 	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("status code: %s", res.Status)
+		defer res.Body.Close()
+		return nil, newResponseError(res)
 	}
 
+	if u.VerifyChecksum {
+		verifyChecksum(res)
+	}
+	return res, nil
+}
+
+// FileDownloadVerified makes a GET call and returns the object once
+// its MD5 has been checked against u.ExpectedMD5, or else against the
+// Content-MD5 header or the ETag of the response. If they differ,
+// ErrChecksumMismatch is returned. The ETag is only used when it is
+// the MD5 of the object, which is not the case for multipart uploads
+// and objects encrypted with SSE-KMS; such objects are returned
+// unverified unless u.ExpectedMD5 is set.
+func (s3 *S3) FileDownloadVerified(u DownloadInput) ([]byte, error) {
+	res, err := s3.download(context.Background(), u)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	h := md5.New()
+	data, err := ioutil.ReadAll(io.TeeReader(res.Body, h))
+	if err != nil {
+		return nil, err
+	}
+	sum := h.Sum(nil)
+
+	expected := strings.ToLower(u.ExpectedMD5)
+	if expected == "" {
+		expected = responseMD5(res.Header)
+	}
+	if expected != "" && expected != hex.EncodeToString(sum) {
+		return nil, ErrChecksumMismatch
+	}
+	return data, nil
+}
+
+// responseMD5 returns the hex-encoded MD5 of the object sent
+// in the response with header, or an empty string if unknown.
+func responseMD5(header http.Header) string {
+	if contentMD5 := header.Get("Content-MD5"); contentMD5 != "" {
+		if sum, err := base64.StdEncoding.DecodeString(contentMD5); err == nil {
+			return hex.EncodeToString(sum)
+		}
+	}
+
+	etag := strings.Trim(header.Get("ETag"), `"`)
+	if len(etag) != 2*md5.Size || header.Get("x-amz-server-side-encryption") == string(SSEKMS) {
+		return ""
+	}
+	if _, err := hex.DecodeString(etag); err != nil {
+		return ""
+	}
+	return strings.ToLower(etag)
+}
+
+// FileDownloadTo makes a GET call and copies the object to w,
+// returning the number of bytes written. It saves callers from
+// handling the response body when streaming the object to a file,
+// an http.ResponseWriter or a hash.
+func (s3 *S3) FileDownloadTo(u DownloadInput, w io.Writer) (int64, error) {
+	body, err := s3.FileDownload(u)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	return io.Copy(w, body)
+}
+
+// FileDownloadRange makes a GET call for the bytes between u.Start
+// and u.End of the object and returns a io.ReadCloser. It can be
+// used to resume an interrupted download. If the range is not
+// satisfiable, the returned error matches ErrRangeNotSatisfiable.
+// After reading the response body, ensure closing the response.
+func (s3 *S3) FileDownloadRange(u DownloadRangeInput) (io.ReadCloser, error) {
+	res, err := s3.downloadRange(u)
+	if err != nil {
+		return nil, err
+	}
 	return res.Body, nil
 }
 
+// downloadRange makes the GET call of FileDownloadRange and returns
+// the response, which is either 206 or 200 if S3 sent the whole object.
+func (s3 *S3) downloadRange(u DownloadRangeInput) (*http.Response, error) {
+	req, err := s3.newRequest(
+		context.Background(), http.MethodGet, u.Bucket, u.ObjectKey, versionQuery(u.VersionID), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	byteRange := "bytes=" + strconv.FormatInt(u.Start, 10) + "-"
+	if u.End > 0 {
+		byteRange += strconv.FormatInt(u.End, 10)
+	}
+	req.Header.Set("Range", byteRange)
+	u.setConditionalHeaders(req.Header)
+	setSSECHeaders(req.Header, u.SSECKey)
+
+	if err := s3.signRequest(req); err != nil {
+		return nil, err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != 206 && res.StatusCode != 200 {
+		defer res.Body.Close()
+		return nil, newResponseError(res)
+	}
+
+	return res, nil
+}
+
+// FileHead makes a HEAD call and returns the metadata of the object
+// without downloading its body. If the object does not exist,
+// the returned error matches ErrNotFound.
+func (s3 *S3) FileHead(u DownloadInput) (HeadOutput, error) {
+	if err := ValidateObjectKey(u.ObjectKey); err != nil {
+		return HeadOutput{}, err
+	}
+	req, err := s3.newRequest(
+		context.Background(), http.MethodHead, u.Bucket, u.ObjectKey, versionQuery(u.VersionID), nil,
+	)
+	if err != nil {
+		return HeadOutput{}, err
+	}
+	setSSECHeaders(req.Header, u.SSECKey)
+
+	if err := s3.signRequest(req); err != nil {
+		return HeadOutput{}, err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return HeadOutput{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return HeadOutput{}, newResponseError(res)
+	}
+
+	out := HeadOutput{
+		ContentLength: res.ContentLength,
+		ContentType:   res.Header.Get("Content-Type"),
+		ETag:          res.Header.Get("ETag"),
+		Metadata:      map[string]string{},
+		Restore:       res.Header.Get("x-amz-restore"),
+	}
+	if lm := res.Header.Get("Last-Modified"); lm != "" {
+		if out.LastModified, err = time.Parse(http.TimeFormat, lm); err != nil {
+			return HeadOutput{}, err
+		}
+	}
+	for k, v := range res.Header {
+		k = strings.ToLower(k)
+		if strings.HasPrefix(k, amzMetaPrefix) && len(v) > 0 {
+			out.Metadata[strings.TrimPrefix(k, amzMetaPrefix)] = v[0]
+		}
+	}
+	return out, nil
+}
+
+// ObjectExists makes a HEAD call to check whether an object exists.
+// It returns false for a missing object and an error for any other
+// failure, such as a 403 when the credentials lack s3:GetObject.
+func (s3 *S3) ObjectExists(bucket, key string) (bool, error) {
+	return s3.exists(bucket, key)
+}
+
+// exists makes a HEAD call to the object key in bucket, or to the
+// bucket if key is empty, and reports whether it returned 200 or 404.
+func (s3 *S3) exists(bucket, key string) (bool, error) {
+	req, err := s3.newRequest(context.Background(), http.MethodHead, bucket, key, "", nil)
+	if err != nil {
+		return false, err
+	}
+
+	if err := s3.signRequest(req); err != nil {
+		return false, err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, newResponseError(res)
+	}
+}
+
 // FileUpload makes a POST call with the file written as multipart
 // and on successful upload, checks for 200 OK.
 func (s3 *S3) FileUpload(u UploadInput) (UploadResponse, error) {
+	return s3.FileUploadWithContext(context.Background(), u)
+}
+
+// FileUploadWithContext is like FileUpload but the request is
+// bound to ctx.
+func (s3 *S3) FileUploadWithContext(ctx context.Context, u UploadInput) (UploadResponse, error) {
+	if err := u.validate(); err != nil {
+		return UploadResponse{}, err
+	}
+	if u.Checksum != "" {
+		return UploadResponse{}, errors.New("Checksum is not supported by FileUpload, use FilePut")
+	}
+	uploadURL, _, err := s3.resolve(u.Bucket, "")
+	if err != nil {
+		return UploadResponse{}, err
+	}
 	fSize, err := detectFileSize(u.Body)
 	if err != nil {
 		return UploadResponse{}, err
 	}
+
+	metaData := map[string]string{
+		"success_action_status": "201", // returns XML doc on success
+	}
+	for k, v := range u.cacheFields() {
+		metaData[k] = v
+	}
+	for k, v := range u.amzFields() {
+		metaData[k] = v
+	}
 	policies, err := s3.CreateUploadPolicies(UploadConfig{
-		UploadURL:          s3.getURL(u.Bucket),
+		UploadURL:          uploadURL,
 		BucketName:         u.Bucket,
 		ObjectKey:          u.ObjectKey,
 		ContentType:        u.ContentType,
 		ContentDisposition: u.ContentDisposition,
 		ACL:                u.ACL,
 		FileSize:           fSize,
-		MetaData: map[string]string{
-			"success_action_status": "201", // returns XML doc on success
-		},
+		MetaData:           metaData,
 	})
 
 	if err != nil {
@@ -323,7 +1136,7 @@ func (s3 *S3) FileUpload(u UploadInput) (UploadResponse, error) {
 	if err != nil {
 		return UploadResponse{}, err
 	}
-	if _, err = io.Copy(fw, u.Body); err != nil {
+	if _, err = io.Copy(fw, withProgress(u.Body, fSize, u.ProgressFunc)); err != nil {
 		return UploadResponse{}, err
 	}
 
@@ -334,7 +1147,7 @@ func (s3 *S3) FileUpload(u UploadInput) (UploadResponse, error) {
 	}
 
 	// Now that you have a form, you can submit it to your handler.
-	req, err := http.NewRequest(http.MethodPost, policies.URL, &b)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, policies.URL, &b)
 	if err != nil {
 		return UploadResponse{}, err
 	}
@@ -342,8 +1155,7 @@ func (s3 *S3) FileUpload(u UploadInput) (UploadResponse, error) {
 	req.Header.Set("Content-Type", w.FormDataContentType())
 
 	// Submit the request
-	client := s3.getClient()
-	res, err := client.Do(req)
+	res, err := s3.do(req)
 	if err != nil {
 		return UploadResponse{}, err
 	}
@@ -355,7 +1167,7 @@ func (s3 *S3) FileUpload(u UploadInput) (UploadResponse, error) {
 	}
 	// Check the response
 	if res.StatusCode != 201 {
-		return UploadResponse{}, fmt.Errorf("status code: %s: %q", res.Status, data)
+		return UploadResponse{}, parseS3Error(res.StatusCode, res.Header, data)
 	}
 
 	var ur UploadResponse
@@ -363,11 +1175,112 @@ func (s3 *S3) FileUpload(u UploadInput) (UploadResponse, error) {
 	return ur, nil
 }
 
+// FilePut makes a PUT call with the file written as the request body
+// and on successful upload, checks for 200 OK. Unlike FileUpload,
+// no POST policy is generated, which makes it the simpler choice
+// for objects that fit in a single request.
+func (s3 *S3) FilePut(u UploadInput) (UploadResponse, error) {
+	return s3.FilePutWithContext(context.Background(), u)
+}
+
+// FilePutWithContext is like FilePut but the request is
+// bound to ctx.
+func (s3 *S3) FilePutWithContext(ctx context.Context, u UploadInput) (UploadResponse, error) {
+	if err := ValidateObjectKey(u.ObjectKey); err != nil {
+		return UploadResponse{}, err
+	}
+	if err := u.validate(); err != nil {
+		return UploadResponse{}, err
+	}
+	fSize, err := detectFileSize(u.Body)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+
+	start, err := u.Body.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+
+	var contentMD5, checksum string
+	if u.VerifyMD5 {
+		if contentMD5, err = hashBody(u.Body, start, md5.New()); err != nil {
+			return UploadResponse{}, err
+		}
+	}
+	if u.Checksum != "" {
+		h, _ := newChecksumHash(u.Checksum)
+		if checksum, err = hashBody(u.Body, start, h); err != nil {
+			return UploadResponse{}, err
+		}
+	}
+
+	// Wrap the body so that the transport does not close
+	// a file owned by the caller.
+	req, err := s3.newRequest(
+		ctx, http.MethodPut, u.Bucket, u.ObjectKey, "",
+		ioutil.NopCloser(withProgress(u.Body, fSize, u.ProgressFunc)),
+	)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+	req.ContentLength = fSize
+	// Allow the request to be retried by rewinding the body.
+	req.GetBody = func() (io.ReadCloser, error) {
+		_, err := u.Body.Seek(start, io.SeekStart)
+		return ioutil.NopCloser(withProgress(u.Body, fSize, u.ProgressFunc)), err
+	}
+
+	u.setHeaders(req.Header)
+	if contentMD5 != "" {
+		req.Header.Set("Content-MD5", contentMD5)
+	}
+	if checksum != "" {
+		req.Header.Set(checksumHeader(u.Checksum), checksum)
+	}
+	// The body is streamed as is instead of being read
+	// into memory to be hashed.
+	req.Header.Set("x-amz-content-sha256", unsignedPayload)
+
+	if err := s3.signRequest(req); err != nil {
+		return UploadResponse{}, err
+	}
+
+	// Submit the request
+	res, err := s3.do(req)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+	defer res.Body.Close()
+
+	// Check the response
+	if res.StatusCode != 200 {
+		return UploadResponse{}, newResponseError(res)
+	}
+
+	return UploadResponse{
+		Location: s3.getURL(u.Bucket, u.ObjectKey),
+		Bucket:   u.Bucket,
+		Key:      u.ObjectKey,
+		ETag:     res.Header.Get("ETag"),
+		MD5:      contentMD5,
+	}, nil
+}
+
 // FileDelete makes a DELETE call with the file written as multipart
 // and on successful upload, checks for 204 No Content.
 func (s3 *S3) FileDelete(u DeleteInput) error {
-	req, err := http.NewRequest(
-		http.MethodDelete, s3.getURL(u.Bucket, u.ObjectKey), nil,
+	return s3.FileDeleteWithContext(context.Background(), u)
+}
+
+// FileDeleteWithContext is like FileDelete but the request is
+// bound to ctx.
+func (s3 *S3) FileDeleteWithContext(ctx context.Context, u DeleteInput) error {
+	if err := ValidateObjectKey(u.ObjectKey); err != nil {
+		return err
+	}
+	req, err := s3.newRequest(
+		ctx, http.MethodDelete, u.Bucket, u.ObjectKey, versionQuery(u.VersionID), nil,
 	)
 	if err != nil {
 		return err
@@ -378,15 +1291,15 @@ func (s3 *S3) FileDelete(u DeleteInput) error {
 	}
 
 	// Submit the request
-	client := s3.getClient()
-	res, err := client.Do(req)
+	res, err := s3.do(req)
 	if err != nil {
 		return err
 	}
+	defer res.Body.Close()
 
 	// Check the response
 	if res.StatusCode != 204 {
-		return fmt.Errorf("status code: %s", res.Status)
+		return newResponseError(res)
 	}
 
 	return nil
@@ -2,12 +2,19 @@ package gos3
 
 import (
 	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 type tConfig struct {
@@ -294,3 +301,553 @@ func TestCustomEndpoint(t *testing.T) {
 		t.Errorf("S3.SetEndpoint() got = %v", s3.Endpoint)
 	}
 }
+
+func TestS3_FilePut(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected 'PUT' request, got '%s'", r.Method)
+		}
+		if r.URL.EscapedPath() != "/bucket/test.txt" {
+			t.Errorf("Expected path '/bucket/test.txt', got '%s'", r.URL.EscapedPath())
+		}
+		if got := r.Header.Get("Content-Type"); got != "text/plain" {
+			t.Errorf("Expected Content-Type 'text/plain', got '%s'", got)
+		}
+		if got := r.Header.Get("x-amz-meta-owner"); got != "gopher" {
+			t.Errorf("Expected x-amz-meta-owner 'gopher', got '%s'", got)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "hello world" {
+			t.Errorf("Expected body 'hello world', got '%s'", body)
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	var written, total int64
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+	resp, err := s3.FilePut(UploadInput{
+		Bucket:      "bucket",
+		ObjectKey:   "test.txt",
+		ContentType: "text/plain",
+		FileName:    "test.txt",
+		Body:        bytes.NewReader([]byte("hello world")),
+		Metadata:    map[string]string{"Owner": "gopher"},
+		ProgressFunc: func(bytesWritten, totalBytes int64) {
+			written, total = bytesWritten, totalBytes
+		},
+	})
+	if err != nil {
+		t.Fatalf("S3.FilePut() error = %v", err)
+	}
+	if resp.ETag != `"etag"` || resp.Key != "test.txt" {
+		t.Errorf("S3.FilePut() got = %v", resp)
+	}
+	if written != 11 || total != 11 {
+		t.Errorf("S3.FilePut() reported progress %d/%d, want 11/11", written, total)
+	}
+}
+
+func TestS3_FileDownloadWithContext(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := s3.FileDownloadWithContext(ctx, DownloadInput{
+		Bucket:    "bucket",
+		ObjectKey: "test.txt",
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("S3.FileDownloadWithContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestS3_FileDownload_Conditional(t *testing.T) {
+	modified := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"etag"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if since := r.Header.Get("If-Modified-Since"); since != "" {
+			if since != "Thu, 02 Jan 2020 03:04:05 GMT" {
+				t.Errorf("If-Modified-Since = %s", since)
+			}
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte("hello world"))
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	for _, u := range []DownloadInput{
+		{Bucket: "bucket", ObjectKey: "test.txt", IfNoneMatch: `"etag"`},
+		{Bucket: "bucket", ObjectKey: "test.txt", IfModifiedSince: &modified},
+	} {
+		if _, err := s3.FileDownload(u); !errors.Is(err, ErrNotModified) {
+			t.Errorf("S3.FileDownload() error = %v, want ErrNotModified", err)
+		}
+	}
+
+	body, err := s3.FileDownload(DownloadInput{Bucket: "bucket", ObjectKey: "test.txt", IfNoneMatch: `"other"`})
+	if err != nil {
+		t.Fatalf("S3.FileDownload() error = %v", err)
+	}
+	body.Close()
+}
+
+func TestS3_FileDownloadTo(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello world"))
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	var buf bytes.Buffer
+	n, err := s3.FileDownloadTo(DownloadInput{Bucket: "bucket", ObjectKey: "test.txt"}, &buf)
+	if err != nil {
+		t.Fatalf("S3.FileDownloadTo() error = %v", err)
+	}
+	if n != 11 || buf.String() != "hello world" {
+		t.Errorf("S3.FileDownloadTo() = %d, %q", n, buf.String())
+	}
+}
+
+func TestS3_FileHead(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("Expected 'HEAD' request, got '%s'", r.Method)
+		}
+		if r.URL.EscapedPath() != "/bucket/test.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Length", "11")
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Fri, 24 May 2013 00:00:00 GMT")
+		w.Header().Set("X-Amz-Meta-Owner", "gopher")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	out, err := s3.FileHead(DownloadInput{Bucket: "bucket", ObjectKey: "test.txt"})
+	if err != nil {
+		t.Fatalf("S3.FileHead() error = %v", err)
+	}
+	if out.ContentLength != 11 || out.ContentType != "text/plain" || out.ETag != `"etag"` {
+		t.Errorf("S3.FileHead() got = %+v", out)
+	}
+	if out.LastModified.IsZero() || out.Metadata["owner"] != "gopher" {
+		t.Errorf("S3.FileHead() got = %+v", out)
+	}
+
+	_, err = s3.FileHead(DownloadInput{Bucket: "bucket", ObjectKey: "missing.txt"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("S3.FileHead() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestS3_ObjectExists(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bucket/test.txt":
+			w.WriteHeader(http.StatusOK)
+		case "/bucket/secret.txt":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	if ok, err := s3.ObjectExists("bucket", "test.txt"); !ok || err != nil {
+		t.Errorf("S3.ObjectExists() = %v, %v, want true", ok, err)
+	}
+	if ok, err := s3.ObjectExists("bucket", "missing.txt"); ok || err != nil {
+		t.Errorf("S3.ObjectExists() = %v, %v, want false", ok, err)
+	}
+	var s3Err *S3Error
+	if _, err := s3.ObjectExists("bucket", "secret.txt"); !errors.As(err, &s3Err) || s3Err.StatusCode != http.StatusForbidden {
+		t.Errorf("S3.ObjectExists() error = %v, want status 403", err)
+	}
+}
+
+func TestS3_FileDownloadRange(t *testing.T) {
+	data := []byte("hello world")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test.txt", time.Time{}, bytes.NewReader(data))
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	tests := []struct {
+		start, end int64
+		want       string
+	}{
+		{0, 4, "hello"},
+		{6, 0, "world"},
+	}
+	for _, tt := range tests {
+		body, err := s3.FileDownloadRange(DownloadRangeInput{
+			DownloadInput: DownloadInput{Bucket: "bucket", ObjectKey: "test.txt"},
+			Start:         tt.start,
+			End:           tt.end,
+		})
+		if err != nil {
+			t.Fatalf("S3.FileDownloadRange() error = %v", err)
+		}
+		got, _ := ioutil.ReadAll(body)
+		body.Close()
+		if string(got) != tt.want {
+			t.Errorf("S3.FileDownloadRange() = %s, want %s", got, tt.want)
+		}
+	}
+
+	_, err := s3.FileDownloadRange(DownloadRangeInput{
+		DownloadInput: DownloadInput{Bucket: "bucket", ObjectKey: "test.txt"},
+		Start:         100,
+	})
+	if !errors.Is(err, ErrRangeNotSatisfiable) {
+		t.Errorf("S3.FileDownloadRange() error = %v, want %v", err, ErrRangeNotSatisfiable)
+	}
+}
+
+func TestURLStyle(t *testing.T) {
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetURLStyle(VirtualHostedStyle)
+
+	if got := s3.getURL("bucket", "xyz/image.png"); got != "https://bucket.s3.us-east-1.amazonaws.com/xyz/image.png" {
+		t.Errorf("S3.getURL() got = %v", got)
+	}
+	if got := s3.getURL(""); got != "https://s3.us-east-1.amazonaws.com" {
+		t.Errorf("S3.getURL() got = %v", got)
+	}
+
+	s3.SetEndpoint("http://localhost:9000")
+	if got := s3.getURL("bucket", "test.txt"); got != "http://bucket.localhost:9000/test.txt" {
+		t.Errorf("S3.getURL() got = %v", got)
+	}
+
+	s3.SetURLStyle(PathStyle)
+	if got := s3.getURL("bucket", "test.txt"); got != "http://localhost:9000/bucket/test.txt" {
+		t.Errorf("S3.getURL() got = %v", got)
+	}
+}
+
+func TestS3_FilePut_MetadataTooLarge(t *testing.T) {
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	_, err := s3.FilePut(UploadInput{
+		Bucket:    "bucket",
+		ObjectKey: "test.txt",
+		Body:      bytes.NewReader(nil),
+		Metadata:  map[string]string{"large": strings.Repeat("x", maxMetadataSize)},
+	})
+	if err != ErrMetadataTooLarge {
+		t.Errorf("S3.FilePut() error = %v, want %v", err, ErrMetadataTooLarge)
+	}
+}
+
+func TestUploadInput_Fields(t *testing.T) {
+	tests := []struct {
+		name    string
+		u       UploadInput
+		want    map[string]string
+		wantErr bool
+	}{
+		{"none", UploadInput{}, map[string]string{}, false},
+		{"sse-s3", UploadInput{Encryption: SSES3}, map[string]string{
+			"x-amz-server-side-encryption": "AES256",
+		}, false},
+		{"sse-kms", UploadInput{Encryption: SSEKMS, KMSKeyID: "key-id"}, map[string]string{
+			"x-amz-server-side-encryption":                "aws:kms",
+			"x-amz-server-side-encryption-aws-kms-key-id": "key-id",
+		}, false},
+		{"unknown", UploadInput{Encryption: "rot13"}, nil, true},
+		{"key without kms", UploadInput{Encryption: SSES3, KMSKeyID: "key-id"}, nil, true},
+		{"storage class", UploadInput{StorageClass: StorageClassGlacier}, map[string]string{
+			"x-amz-storage-class": "GLACIER",
+		}, false},
+		{"unknown storage class", UploadInput{StorageClass: "COLD"}, nil, true},
+		{"caching", UploadInput{
+			CacheControl: "max-age=3600",
+			Expires:      time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("IST", 19800)),
+		}, map[string]string{
+			"Cache-Control": "max-age=3600",
+			"Expires":       "Wed, 01 Jan 2020 21:34:05 GMT",
+		}, false},
+		{"website redirect", UploadInput{WebsiteRedirectLocation: "/new/index.html"}, map[string]string{
+			"x-amz-website-redirect-location": "/new/index.html",
+		}, false},
+		{"sse-c", UploadInput{SSECKey: bytes.Repeat([]byte{'k'}, 32)}, map[string]string{
+			"x-amz-server-side-encryption-customer-algorithm": "AES256",
+			"x-amz-server-side-encryption-customer-key":       "a2tra2tra2tra2tra2tra2tra2tra2tra2tra2tra2s=",
+			"x-amz-server-side-encryption-customer-key-MD5":   "mT2HRsMGJ5IX5C+0rreZ8Q==",
+		}, false},
+		{"short sse-c key", UploadInput{SSECKey: []byte("key")}, nil, true},
+		{"sse-c with sse-s3", UploadInput{SSECKey: bytes.Repeat([]byte{'k'}, 32), Encryption: SSES3}, nil, true},
+		{"unknown checksum", UploadInput{Checksum: "MD4"}, nil, true},
+	}
+	for _, tt := range tests {
+		if err := tt.u.validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if tt.wantErr {
+			continue
+		}
+		h := http.Header{}
+		tt.u.setHeaders(h)
+		for k, v := range tt.want {
+			if h.Get(k) != v {
+				t.Errorf("%s: header %s = %s, want %s", tt.name, k, h.Get(k), v)
+			}
+		}
+		if got := h.Get("x-amz-server-side-encryption"); len(tt.want) == 0 && got != "" {
+			t.Errorf("%s: unexpected header x-amz-server-side-encryption = %s", tt.name, got)
+		}
+	}
+}
+
+func TestS3_FileUpload_CacheFields(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("ParseMultipartForm() error = %v", err)
+		}
+		if got := r.FormValue("Cache-Control"); got != "no-cache" {
+			t.Errorf("form field Cache-Control = %s", got)
+		}
+		policy, _ := base64.StdEncoding.DecodeString(r.FormValue("Policy"))
+		if !bytes.Contains(policy, []byte(`{"Cache-Control":"no-cache"}`)) {
+			t.Errorf("no Cache-Control condition in policy %s", policy)
+		}
+		if r.FormValue("Expires") != "" {
+			t.Errorf("unexpected form field Expires = %s", r.FormValue("Expires"))
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	_, err := s3.FileUpload(UploadInput{
+		Bucket:       "bucket",
+		ObjectKey:    "test.txt",
+		FileName:     "test.txt",
+		CacheControl: "no-cache",
+		Body:         strings.NewReader("hello world"),
+	})
+	if err != nil {
+		t.Errorf("S3.FileUpload() error = %v", err)
+	}
+}
+
+func TestS3_signRequestWithBody(t *testing.T) {
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+
+	sign := func(body string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPut, s3.getURL("bucket")+"?policy", strings.NewReader(body))
+		req.Header.Set("Date", "Fri, 24 May 2013 00:00:00 GMT")
+		if err := s3.signRequestWithBody(req, []byte(body)); err != nil {
+			t.Fatalf("S3.signRequestWithBody() error = %v", err)
+		}
+		return req
+	}
+
+	req := sign("{}")
+	if got := req.Header.Get("x-amz-content-sha256"); got != "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a" {
+		t.Errorf("x-amz-content-sha256 = %s", got)
+	}
+	if req.Header.Get("Authorization") == sign("[]").Header.Get("Authorization") {
+		t.Errorf("S3.signRequestWithBody() signature does not cover the body")
+	}
+}
+
+func TestS3_FileDownloadVerified(t *testing.T) {
+	data := []byte("hello world")
+	sum := md5.Sum(data)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bucket/good.txt":
+			w.Header().Set("ETag", etag)
+		case "/bucket/corrupt.txt":
+			w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+		case "/bucket/content-md5.txt":
+			w.Header().Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+		case "/bucket/multipart.txt":
+			w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e-2"`)
+		}
+		w.Write(data)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	tests := []struct {
+		key, expectedMD5 string
+		wantErr          error
+	}{
+		{"good.txt", "", nil},
+		{"corrupt.txt", "", ErrChecksumMismatch},
+		{"content-md5.txt", "", nil},
+		{"multipart.txt", "", nil},
+		{"multipart.txt", "d41d8cd98f00b204e9800998ecf8427e", ErrChecksumMismatch},
+		{"multipart.txt", strings.ToUpper(hex.EncodeToString(sum[:])), nil},
+	}
+	for _, tt := range tests {
+		got, err := s3.FileDownloadVerified(DownloadInput{Bucket: "bucket", ObjectKey: tt.key, ExpectedMD5: tt.expectedMD5})
+		if err != tt.wantErr {
+			t.Errorf("S3.FileDownloadVerified(%s) error = %v, want %v", tt.key, err, tt.wantErr)
+		}
+		if err == nil && !bytes.Equal(got, data) {
+			t.Errorf("S3.FileDownloadVerified(%s) = %s", tt.key, got)
+		}
+	}
+}
+
+func TestS3_FilePut_VerifyMD5(t *testing.T) {
+	data := []byte("hello world")
+	sum := md5.Sum(data)
+	want := base64.StdEncoding.EncodeToString(sum[:])
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if got := r.Header.Get("Content-MD5"); got != want || !bytes.Equal(body, data) {
+			t.Errorf("Content-MD5 = %s for body %q, want %s", got, body, want)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	resp, err := s3.FilePut(UploadInput{
+		Bucket:    "bucket",
+		ObjectKey: "test.txt",
+		VerifyMD5: true,
+		Body:      bytes.NewReader(data),
+	})
+	if err != nil {
+		t.Fatalf("S3.FilePut() error = %v", err)
+	}
+	if resp.MD5 != want {
+		t.Errorf("S3.FilePut() MD5 = %s, want %s", resp.MD5, want)
+	}
+}
+
+func TestS3_SSEC(t *testing.T) {
+	key := bytes.Repeat([]byte{'k'}, 32)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-amz-server-side-encryption-customer-algorithm") != "AES256" ||
+			r.Header.Get("x-amz-server-side-encryption-customer-key") != base64.StdEncoding.EncodeToString(key) ||
+			r.Header.Get("x-amz-server-side-encryption-customer-key-MD5") != "mT2HRsMGJ5IX5C+0rreZ8Q==" {
+			t.Errorf("%s: missing SSE-C headers in %v", r.Method, r.Header)
+		}
+		if !strings.Contains(r.Header.Get("Authorization"), "x-amz-server-side-encryption-customer-key-md5") {
+			t.Errorf("%s: SSE-C headers are not signed: %s", r.Method, r.Header.Get("Authorization"))
+		}
+		io.WriteString(w, "hello world")
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	_, err := s3.FilePut(UploadInput{
+		Bucket:    "bucket",
+		ObjectKey: "test.txt",
+		SSECKey:   key,
+		Body:      strings.NewReader("hello world"),
+	})
+	if err != nil {
+		t.Fatalf("S3.FilePut() error = %v", err)
+	}
+
+	body, err := s3.FileDownload(DownloadInput{Bucket: "bucket", ObjectKey: "test.txt", SSECKey: key})
+	if err != nil {
+		t.Fatalf("S3.FileDownload() error = %v", err)
+	}
+	body.Close()
+}
+
+func TestS3_FileDownloadWithProgress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/bucket/chunked.txt" {
+			// Flushing before writing the body makes the
+			// response chunked, without a Content-Length.
+			w.(http.Flusher).Flush()
+		}
+		io.WriteString(w, "hello world")
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	for key, wantTotal := range map[string]int64{"test.txt": 11, "chunked.txt": -1} {
+		var downloaded, total int64
+		body, err := s3.FileDownloadWithProgress(DownloadInput{Bucket: "bucket", ObjectKey: key}, func(n, t int64) {
+			downloaded, total = n, t
+		})
+		if err != nil {
+			t.Fatalf("S3.FileDownloadWithProgress() error = %v", err)
+		}
+		data, _ := ioutil.ReadAll(body)
+		body.Close()
+		if string(data) != "hello world" || downloaded != 11 || total != wantTotal {
+			t.Errorf("S3.FileDownloadWithProgress(%s) reported progress %d/%d, want 11/%d", key, downloaded, total, wantTotal)
+		}
+	}
+}
+
+func TestS3_SetRequesterPays(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("x-amz-request-payer"); got != "requester" {
+			t.Errorf("x-amz-request-payer = %q, want requester", got)
+		}
+		if !strings.Contains(r.Header.Get("Authorization"), "x-amz-request-payer") {
+			t.Errorf("x-amz-request-payer is not signed: %s", r.Header.Get("Authorization"))
+		}
+		io.WriteString(w, "hello world")
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL).SetRequesterPays(true)
+
+	body, err := s3.FileDownload(DownloadInput{Bucket: "bucket", ObjectKey: "test.txt"})
+	if err != nil {
+		t.Fatalf("S3.FileDownload() error = %v", err)
+	}
+	body.Close()
+
+	if !s3.withRegion("eu-west-1").RequesterPays {
+		t.Errorf("S3.withRegion() did not keep RequesterPays")
+	}
+}
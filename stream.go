@@ -0,0 +1,70 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// StreamUploadInput is passed to StreamUpload as a parameter.
+// The Body of the embedded UploadInput is ignored in favour
+// of the io.Reader below.
+type StreamUploadInput struct {
+	UploadInput
+
+	// Body is read until EOF, its size does not need to be known.
+	Body io.Reader
+
+	// PartSize is the amount of the body held in memory at once.
+	// Defaults to 5 MB, which is also the minimum allowed by S3.
+	PartSize int64
+
+	// Size is the size of the body, if known. Bodies of up to 5 GB
+	// of a known size are streamed in a single PUT request without
+	// being buffered, using aws-chunked signing.
+	Size int64
+}
+
+// StreamUpload uploads a body of unknown size, such as os.Stdin or
+// a network stream, which cannot be uploaded with FilePut since
+// it needs to seek the body to find its size.
+//
+// S3 does not accept PUT requests without a known length, so unless
+// u.Size is set, the body is buffered u.PartSize bytes at a time. If it
+// fits in a single part, it is uploaded with FilePut, otherwise each
+// part is uploaded as soon as it is read using a multipart upload.
+func (s3 *S3) StreamUpload(u StreamUploadInput) (UploadResponse, error) {
+	if err := u.validate(); err != nil {
+		return UploadResponse{}, err
+	}
+	// The checksum of the body would only be known once it is
+	// sent, it is buffered so that the checksums of its parts
+	// are sent along with them instead.
+	if u.Size > 0 && u.Size <= maxPutSize && u.Checksum == "" {
+		return s3.putChunked(u.UploadInput, u.Body, u.Size)
+	}
+
+	partSize := u.PartSize
+	if partSize == 0 {
+		partSize = minPartSize
+	}
+	if partSize < minPartSize {
+		return UploadResponse{}, fmt.Errorf("part size %d is smaller than the minimum of %d bytes", partSize, minPartSize)
+	}
+
+	first := make([]byte, partSize)
+	n, err := io.ReadFull(u.Body, first)
+	switch err {
+	case io.EOF, io.ErrUnexpectedEOF:
+		// The whole body fits in a single request.
+		u.UploadInput.Body = bytes.NewReader(first[:n])
+		return s3.FilePut(u.UploadInput)
+	case nil:
+		return s3.uploadParts(u.UploadInput, io.MultiReader(bytes.NewReader(first), u.Body), partSize, 1, 0)
+	default:
+		return UploadResponse{}, err
+	}
+}
@@ -0,0 +1,58 @@
+package gos3
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestS3_StreamUpload(t *testing.T) {
+	t.Run("small", func(t *testing.T) {
+		var got []byte
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut || r.ContentLength != 11 {
+				t.Errorf("unexpected request %s %s of %d bytes", r.Method, r.URL, r.ContentLength)
+			}
+			got, _ = ioutil.ReadAll(r.Body)
+		}))
+		defer ts.Close()
+
+		s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+		s3.SetEndpoint(ts.URL)
+
+		// Hide the Seek method of the reader.
+		body := io.MultiReader(bytes.NewReader([]byte("hello world")))
+		if _, err := s3.StreamUpload(StreamUploadInput{
+			UploadInput: UploadInput{Bucket: "bucket", ObjectKey: "key"},
+			Body:        body,
+		}); err != nil {
+			t.Fatalf("S3.StreamUpload() error = %v", err)
+		}
+		if string(got) != "hello world" {
+			t.Errorf("S3.StreamUpload() uploaded %q", got)
+		}
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		m := &multipartServer{t: t, parts: map[string][]byte{}}
+		ts := httptest.NewServer(m)
+		defer ts.Close()
+
+		s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+		s3.SetEndpoint(ts.URL)
+
+		data := bytes.Repeat([]byte("0123456789"), minPartSize/5)
+		if _, err := s3.StreamUpload(StreamUploadInput{
+			UploadInput: UploadInput{Bucket: "bucket", ObjectKey: "key"},
+			Body:        io.MultiReader(bytes.NewReader(data)),
+		}); err != nil {
+			t.Fatalf("S3.StreamUpload() error = %v", err)
+		}
+		if len(m.parts) != 2 || !bytes.Equal(m.complete, data) {
+			t.Errorf("S3.StreamUpload() uploaded %d parts", len(m.parts))
+		}
+	})
+}
@@ -0,0 +1,228 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// stsEndpoint is the global endpoint of STS,
+	// whose requests are signed for us-east-1.
+	stsEndpoint = "https://sts.amazonaws.com"
+	stsService  = "sts"
+	stsVersion  = "2011-06-15"
+
+	// webIdentityRefreshBefore is how long before they expire the
+	// credentials of NewUsingWebIdentity are refreshed.
+	webIdentityRefreshBefore = 5 * time.Minute
+)
+
+// assumeRoleResponse receives the AssumeRoleResponse XML.
+type assumeRoleResponse struct {
+	Result struct {
+		AssumedRoleUser struct {
+			Arn           string `xml:"Arn"`
+			AssumedRoleID string `xml:"AssumedRoleId"`
+		} `xml:"AssumedRoleUser"`
+		Credentials stsCredentials `xml:"Credentials"`
+	} `xml:"AssumeRoleResult"`
+}
+
+// assumeRoleWithWebIdentityResponse receives
+// the AssumeRoleWithWebIdentityResponse XML.
+type assumeRoleWithWebIdentityResponse struct {
+	Result struct {
+		Credentials stsCredentials `xml:"Credentials"`
+	} `xml:"AssumeRoleWithWebIdentityResult"`
+}
+
+// stsCredentials are the temporary credentials returned by STS.
+type stsCredentials struct {
+	AccessKeyID     string    `xml:"AccessKeyId"`
+	SecretAccessKey string    `xml:"SecretAccessKey"`
+	SessionToken    string    `xml:"SessionToken"`
+	Expiration      time.Time `xml:"Expiration"`
+}
+
+func (c stsCredentials) credentials() credentials {
+	return credentials{
+		AccessKey:  c.AccessKeyID,
+		SecretKey:  c.SecretAccessKey,
+		Token:      c.SessionToken,
+		Expiration: c.Expiration,
+	}
+}
+
+// stsErrorResponse receives the ErrorResponse XML of STS,
+// which wraps the error document sent by S3.
+type stsErrorResponse struct {
+	Error     S3Error `xml:"Error"`
+	RequestID string  `xml:"RequestId"`
+}
+
+// NewUsingAssumedRole returns an instance of S3 using the temporary
+// credentials of the IAM role roleARN, assumed with STS by the
+// credentials of base, eg. to access the buckets of another account.
+// sessionName identifies the session in CloudTrail. The credentials
+// are valid for an hour and are not refreshed.
+func NewUsingAssumedRole(region, roleARN, sessionName string, base *S3) (*S3, error) {
+	return NewUsingAssumedRoleWithExternalID(region, roleARN, sessionName, "", base)
+}
+
+// NewUsingAssumedRoleWithExternalID is like NewUsingAssumedRole, but sends
+// externalID along, as required by the trust policy of some cross-account
+// roles. An empty externalID is not sent.
+func NewUsingAssumedRoleWithExternalID(region, roleARN, sessionName, externalID string, base *S3) (*S3, error) {
+	params := url.Values{}
+	params.Set("RoleArn", roleARN)
+	params.Set("RoleSessionName", sessionName)
+	if externalID != "" {
+		params.Set("ExternalId", externalID)
+	}
+
+	creds, err := base.assumeRole(params)
+	if err != nil {
+		return nil, err
+	}
+
+	s3 := New(region, creds.AccessKey, creds.SecretKey)
+	s3.SetToken(creds.Token)
+	s3.Client = base.Client
+	return s3, nil
+}
+
+// assumeRole makes a signed AssumeRole call to STS with
+// params and returns the credentials of the assumed role.
+func (s3 *S3) assumeRole(params url.Values) (credentials, error) {
+	var resp assumeRoleResponse
+	if err := s3.callSTS("AssumeRole", params, &resp); err != nil {
+		return credentials{}, err
+	}
+	return resp.Result.Credentials.credentials(), nil
+}
+
+// callSTS makes a POST call of action to STS with params and decodes
+// the response into out. The request is signed, unless s3 has
+// no credentials, as for AssumeRoleWithWebIdentity.
+func (s3 *S3) callSTS(action string, params url.Values, out interface{}) error {
+	params.Set("Action", action)
+	params.Set("Version", stsVersion)
+	body := []byte(params.Encode())
+
+	req, err := http.NewRequest(http.MethodPost, stsEndpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s3.mu.RLock()
+	signed := s3.AccessKey != ""
+	s3.mu.RUnlock()
+	if signed {
+		// The global endpoint of STS is signed for us-east-1,
+		// whatever the region of the client.
+		sum := sha256.Sum256(body)
+		req.Header.Set("x-amz-content-sha256", hex.EncodeToString(sum[:]))
+		if err := s3.signRequestFor(req, globalRegion, stsService); err != nil {
+			return err
+		}
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return parseSTSError(res.StatusCode, res.Header, data)
+	}
+	return xml.Unmarshal(data, out)
+}
+
+// parseSTSError returns the error sent by STS as an *S3Error,
+// so that it can be matched with the same sentinel errors.
+func parseSTSError(statusCode int, header http.Header, data []byte) *S3Error {
+	var resp stsErrorResponse
+	if err := xml.Unmarshal(data, &resp); err != nil || resp.Error.Code == "" {
+		return parseS3Error(statusCode, header, data)
+	}
+	e := &resp.Error
+	e.StatusCode = statusCode
+	e.RequestID = resp.RequestID
+	if e.RequestID == "" {
+		e.RequestID = header.Get("x-amzn-RequestId")
+	}
+	return e
+}
+
+// NewUsingWebIdentity returns an instance of S3 using the credentials of
+// the IAM role in AWS_ROLE_ARN, assumed with the web identity token in
+// the file at AWS_WEB_IDENTITY_TOKEN_FILE, as set up in Kubernetes pods
+// by IAM Roles for Service Accounts. The session is named after
+// AWS_ROLE_SESSION_NAME if set. A background goroutine reads the token
+// again and refreshes the credentials 5 minutes before they expire,
+// it runs for the lifetime of the process.
+func NewUsingWebIdentity(region string) (*S3, error) {
+	return newUsingWebIdentityImpl(New(globalRegion, "", ""), region)
+}
+
+func newUsingWebIdentityImpl(sts *S3, region string) (*S3, error) {
+	roleARN, tokenFile := os.Getenv("AWS_ROLE_ARN"), os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	if roleARN == "" || tokenFile == "" {
+		return nil, errors.New("AWS_ROLE_ARN or AWS_WEB_IDENTITY_TOKEN_FILE is not set")
+	}
+	sessionName := os.Getenv("AWS_ROLE_SESSION_NAME")
+	if sessionName == "" {
+		sessionName = "gos3-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+
+	fetch := func() (credentials, error) {
+		// The token is rotated, it is read
+		// again for every refresh.
+		token, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return credentials{}, err
+		}
+
+		params := url.Values{}
+		params.Set("RoleArn", roleARN)
+		params.Set("RoleSessionName", sessionName)
+		params.Set("WebIdentityToken", strings.TrimSpace(string(token)))
+
+		var resp assumeRoleWithWebIdentityResponse
+		if err := sts.callSTS("AssumeRoleWithWebIdentity", params, &resp); err != nil {
+			return credentials{}, err
+		}
+		return resp.Result.Credentials.credentials(), nil
+	}
+
+	creds, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	s3 := New(region, creds.AccessKey, creds.SecretKey)
+	s3.SetToken(creds.Token)
+	s3.Client = sts.Client
+	go s3.refreshCredentials(context.Background(), creds.Expiration, webIdentityRefreshBefore, fetch)
+	return s3, nil
+}
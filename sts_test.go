@@ -0,0 +1,133 @@
+package gos3
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewUsingAssumedRole(t *testing.T) {
+	base := New("us-east-1", "AccessKey", "SuperSecretKey")
+	base.SetClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.String() != "https://sts.amazonaws.com/" || !strings.Contains(r.Header.Get("Authorization"), "AccessKey/") ||
+			!strings.Contains(r.Header.Get("Authorization"), "/us-east-1/sts/aws4_request") {
+			t.Errorf("unexpected request to %s signed with %s", r.URL, r.Header.Get("Authorization"))
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(data))
+		if form.Get("Action") != "AssumeRole" || form.Get("RoleArn") != "arn:aws:iam::123456789012:role/reader" ||
+			form.Get("RoleSessionName") != "session" {
+			t.Errorf("unexpected form %v", form)
+		}
+
+		if form.Get("ExternalId") != "secret-id" {
+			return &http.Response{
+				StatusCode: http.StatusForbidden,
+				Body: ioutil.NopCloser(strings.NewReader(`<ErrorResponse><Error><Type>Sender</Type><Code>AccessDenied</Code>` +
+					`<Message>not authorized to perform sts:AssumeRole</Message></Error><RequestId>req-1</RequestId></ErrorResponse>`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><AssumeRoleResult>` +
+				`<AssumedRoleUser><Arn>arn:aws:sts::123456789012:assumed-role/reader/session</Arn><AssumedRoleId>ARO123:session</AssumedRoleId></AssumedRoleUser>` +
+				`<Credentials><AccessKeyId>ASIAKEY</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>token</SessionToken>` +
+				`<Expiration>2030-01-01T00:00:00Z</Expiration></Credentials></AssumeRoleResult></AssumeRoleResponse>`)),
+		}, nil
+	})})
+
+	s3, err := NewUsingAssumedRoleWithExternalID("eu-west-1", "arn:aws:iam::123456789012:role/reader", "session", "secret-id", base)
+	if err != nil {
+		t.Fatalf("NewUsingAssumedRole() error = %v", err)
+	}
+	if s3.AccessKey != "ASIAKEY" || s3.SecretKey != "secret" || s3.Token != "token" || s3.Region != "eu-west-1" {
+		t.Errorf("NewUsingAssumedRole() got = %+v", s3)
+	}
+
+	_, err = NewUsingAssumedRole("eu-west-1", "arn:aws:iam::123456789012:role/reader", "session", base)
+	var s3Err *S3Error
+	if !errors.Is(err, ErrAccessDenied) || !errors.As(err, &s3Err) || s3Err.RequestID != "req-1" {
+		t.Errorf("NewUsingAssumedRole() error = %v, want ErrAccessDenied", err)
+	}
+}
+
+func TestNewUsingWebIdentity(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gos3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("token-1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/pod")()
+	defer setenv("AWS_WEB_IDENTITY_TOKEN_FILE", tokenFile)()
+	defer setenv("AWS_ROLE_SESSION_NAME", "pod-session")()
+
+	sts := New(globalRegion, "", "")
+	sts.SetClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.String() != "https://sts.amazonaws.com/" || r.Header.Get("Authorization") != "" {
+			t.Errorf("unexpected request to %s signed with %s", r.URL, r.Header.Get("Authorization"))
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(data))
+		if form.Get("Action") != "AssumeRoleWithWebIdentity" || form.Get("RoleArn") != "arn:aws:iam::123456789012:role/pod" ||
+			form.Get("RoleSessionName") != "pod-session" {
+			t.Errorf("unexpected form %v", form)
+		}
+
+		// The first credentials are refreshed right away,
+		// the following ones are valid for an hour.
+		token := form.Get("WebIdentityToken")
+		expiration := time.Now().Add(webIdentityRefreshBefore + 100*time.Millisecond)
+		if token != "token-1" {
+			expiration = time.Now().Add(time.Hour)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`<AssumeRoleWithWebIdentityResponse><AssumeRoleWithWebIdentityResult>` +
+				`<Credentials><AccessKeyId>key-` + token + `</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>` + token + `</SessionToken>` +
+				`<Expiration>` + expiration.UTC().Format(time.RFC3339Nano) + `</Expiration></Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`)),
+		}, nil
+	})})
+
+	s3, err := newUsingWebIdentityImpl(sts, "eu-west-1")
+	if err != nil {
+		t.Fatalf("NewUsingWebIdentity() error = %v", err)
+	}
+	if s3.AccessKey != "key-token-1" || s3.Token != "token-1" || s3.Region != "eu-west-1" {
+		t.Errorf("NewUsingWebIdentity() got = %+v", s3)
+	}
+
+	if err := ioutil.WriteFile(tokenFile, []byte("token-2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		s3.mu.RLock()
+		accessKey, token := s3.AccessKey, s3.Token
+		s3.mu.RUnlock()
+		if accessKey == "key-token-2" {
+			if token != "token-2" {
+				t.Errorf("NewUsingWebIdentity() got = %s, %s", accessKey, token)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("NewUsingWebIdentity() credentials were not refreshed")
+}
+
+func TestNewUsingWebIdentity_MissingEnv(t *testing.T) {
+	defer setenv("AWS_ROLE_ARN", "")()
+	if _, err := NewUsingWebIdentity("eu-west-1"); err == nil {
+		t.Errorf("NewUsingWebIdentity() expected an error without AWS_ROLE_ARN")
+	}
+}
@@ -0,0 +1,128 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+)
+
+// SyncInput is passed to SyncDirectory as a parameter.
+type SyncInput struct {
+	Bucket string
+	// Prefix is prepended to the path of each file
+	// relative to LocalDir to form its object key.
+	Prefix   string
+	LocalDir string
+
+	// Concurrency is the number of files uploaded at once,
+	// defaults to 5.
+	Concurrency int
+	// SkipExisting skips files for which an object
+	// of the same size already exists.
+	SkipExisting bool
+}
+
+// SyncReport is returned by SyncDirectory.
+type SyncReport struct {
+	Uploaded int
+	Skipped  int
+	Failed   int
+	Errors   []error
+}
+
+// SyncDirectory uploads every file under in.LocalDir to in.Bucket
+// using FilePut. Failing files do not stop the sync, they are
+// counted in the report along with their errors. The returned
+// error is only set if the directory could not be walked.
+func (s3 *S3) SyncDirectory(in SyncInput) (SyncReport, error) {
+	concurrency := in.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	var (
+		report SyncReport
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+	)
+	files := make(chan string)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range files {
+				uploaded, err := s3.syncFile(in, file)
+				mu.Lock()
+				switch {
+				case err != nil:
+					report.Failed++
+					report.Errors = append(report.Errors, fmt.Errorf("%s: %w", file, err))
+				case uploaded:
+					report.Uploaded++
+				default:
+					report.Skipped++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	err := filepath.Walk(in.LocalDir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			files <- file
+		}
+		return nil
+	})
+	close(files)
+	wg.Wait()
+
+	return report, err
+}
+
+// syncFile uploads a single file for SyncDirectory and
+// reports whether it was uploaded or skipped.
+func (s3 *S3) syncFile(in SyncInput, file string) (bool, error) {
+	rel, err := filepath.Rel(in.LocalDir, file)
+	if err != nil {
+		return false, err
+	}
+	key := path.Join(in.Prefix, filepath.ToSlash(rel))
+
+	f, err := os.Open(file)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	if in.SkipExisting {
+		info, err := f.Stat()
+		if err != nil {
+			return false, err
+		}
+		head, err := s3.FileHead(DownloadInput{Bucket: in.Bucket, ObjectKey: key})
+		if err == nil && head.ContentLength == info.Size() {
+			return false, nil
+		}
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return false, err
+		}
+	}
+
+	_, err = s3.FilePut(UploadInput{
+		Bucket:      in.Bucket,
+		ObjectKey:   key,
+		ContentType: mime.TypeByExtension(filepath.Ext(file)),
+		Body:        f,
+	})
+	return err == nil, err
+}
@@ -0,0 +1,77 @@
+package gos3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestS3_SyncDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gos3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"a.txt":        "hello",
+		"sub/b.txt":    "world",
+		"sub/same.txt": "unchanged",
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var (
+		mu  sync.Mutex
+		put []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodHead:
+			if r.URL.Path == "/bucket/backup/sub/same.txt" {
+				w.Header().Set("Content-Length", "9")
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		case http.MethodPut:
+			mu.Lock()
+			put = append(put, r.URL.Path)
+			mu.Unlock()
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	report, err := s3.SyncDirectory(SyncInput{
+		Bucket:       "bucket",
+		Prefix:       "backup",
+		LocalDir:     dir,
+		Concurrency:  2,
+		SkipExisting: true,
+	})
+	if err != nil {
+		t.Fatalf("S3.SyncDirectory() error = %v", err)
+	}
+	if report.Uploaded != 2 || report.Skipped != 1 || report.Failed != 0 {
+		t.Errorf("S3.SyncDirectory() report = %+v", report)
+	}
+
+	sort.Strings(put)
+	if len(put) != 2 || put[0] != "/bucket/backup/a.txt" || put[1] != "/bucket/backup/sub/b.txt" {
+		t.Errorf("S3.SyncDirectory() uploaded %v", put)
+	}
+}
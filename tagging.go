@@ -0,0 +1,90 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"sort"
+)
+
+// TagSet contains the tags of an object, keyed by name.
+type TagSet map[string]string
+
+type tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+// tagging is the XML representation of a TagSet.
+type tagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	TagSet  []tag    `xml:"TagSet>Tag"`
+}
+
+func newTagging(tags TagSet) tagging {
+	var t tagging
+	for k, v := range tags {
+		t.TagSet = append(t.TagSet, tag{Key: k, Value: v})
+	}
+	// Keep the body stable for a given set of tags.
+	sort.Slice(t.TagSet, func(i, j int) bool {
+		return t.TagSet[i].Key < t.TagSet[j].Key
+	})
+	return t
+}
+
+func (t tagging) tags() TagSet {
+	tags := TagSet{}
+	for _, tag := range t.TagSet {
+		tags[tag.Key] = tag.Value
+	}
+	return tags
+}
+
+// PutObjectTagging makes a PUT call to replace
+// the tags of an object with tags.
+func (s3 *S3) PutObjectTagging(bucket, key string, tags TagSet) error {
+	return s3.doXML(http.MethodPut, bucket, key, "?tagging", nil, newTagging(tags), nil)
+}
+
+// GetObjectTagging makes a GET call and returns the tags of an object.
+func (s3 *S3) GetObjectTagging(bucket, key string) (TagSet, error) {
+	var t tagging
+	if err := s3.doXML(http.MethodGet, bucket, key, "?tagging", nil, nil, &t); err != nil {
+		return nil, err
+	}
+	return t.tags(), nil
+}
+
+// DeleteObjectTagging makes a DELETE call to remove all the tags of an object.
+func (s3 *S3) DeleteObjectTagging(bucket, key string) error {
+	return s3.doXML(http.MethodDelete, bucket, key, "?tagging", nil, nil, nil)
+}
+
+// PutBucketTagging makes a PUT call to replace the tags of a
+// bucket with tags, eg. to allocate its costs.
+func (s3 *S3) PutBucketTagging(bucket string, tags map[string]string) error {
+	return s3.doXML(http.MethodPut, bucket, "", "?tagging", nil, newTagging(tags), nil)
+}
+
+// GetBucketTagging makes a GET call and returns the tags of a bucket,
+// which are empty if the bucket has none.
+func (s3 *S3) GetBucketTagging(bucket string) (map[string]string, error) {
+	var t tagging
+	if err := s3.doXML(http.MethodGet, bucket, "", "?tagging", nil, nil, &t); err != nil {
+		var s3Err *S3Error
+		if errors.As(err, &s3Err) && s3Err.Code == "NoSuchTagSet" {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	return t.tags(), nil
+}
+
+// DeleteBucketTagging makes a DELETE call to remove all the tags of a bucket.
+func (s3 *S3) DeleteBucketTagging(bucket string) error {
+	return s3.doXML(http.MethodDelete, bucket, "", "?tagging", nil, nil, nil)
+}
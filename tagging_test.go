@@ -0,0 +1,106 @@
+package gos3
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestS3_ObjectTagging(t *testing.T) {
+	var stored []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["tagging"]; !ok || r.URL.EscapedPath() != "/bucket/test.txt" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			if r.Header.Get("Content-MD5") == "" {
+				t.Errorf("missing Content-MD5 header")
+			}
+			stored, _ = ioutil.ReadAll(r.Body)
+		case http.MethodGet:
+			w.Write(stored)
+		case http.MethodDelete:
+			stored = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	tags := TagSet{"project": "gos3", "env": "test"}
+	if err := s3.PutObjectTagging("bucket", "test.txt", tags); err != nil {
+		t.Fatalf("S3.PutObjectTagging() error = %v", err)
+	}
+	var sent tagging
+	if err := xml.Unmarshal(stored, &sent); err != nil || len(sent.TagSet) != 2 || sent.TagSet[0].Key != "env" {
+		t.Errorf("S3.PutObjectTagging() sent = %s", stored)
+	}
+
+	got, err := s3.GetObjectTagging("bucket", "test.txt")
+	if err != nil {
+		t.Fatalf("S3.GetObjectTagging() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, tags) {
+		t.Errorf("S3.GetObjectTagging() = %v, want %v", got, tags)
+	}
+
+	if err := s3.DeleteObjectTagging("bucket", "test.txt"); err != nil {
+		t.Fatalf("S3.DeleteObjectTagging() error = %v", err)
+	}
+}
+
+func TestS3_BucketTagging(t *testing.T) {
+	var stored []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["tagging"]; !ok || r.URL.EscapedPath() != "/bucket" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			stored, _ = ioutil.ReadAll(r.Body)
+		case http.MethodGet:
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`<Error><Code>NoSuchTagSet</Code><Message>The TagSet does not exist</Message></Error>`))
+				return
+			}
+			w.Write(stored)
+		case http.MethodDelete:
+			stored = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	tags := map[string]string{"cost-center": "1234"}
+	if err := s3.PutBucketTagging("bucket", tags); err != nil {
+		t.Fatalf("S3.PutBucketTagging() error = %v", err)
+	}
+	if want := `<Tagging><TagSet><Tag><Key>cost-center</Key><Value>1234</Value></Tag></TagSet></Tagging>`; string(stored) != want {
+		t.Errorf("S3.PutBucketTagging() sent = %s, want %s", stored, want)
+	}
+
+	got, err := s3.GetBucketTagging("bucket")
+	if err != nil {
+		t.Fatalf("S3.GetBucketTagging() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, tags) {
+		t.Errorf("S3.GetBucketTagging() = %v, want %v", got, tags)
+	}
+
+	if err := s3.DeleteBucketTagging("bucket"); err != nil {
+		t.Fatalf("S3.DeleteBucketTagging() error = %v", err)
+	}
+	if got, err := s3.GetBucketTagging("bucket"); err != nil || len(got) != 0 {
+		t.Errorf("S3.GetBucketTagging() = %v, %v, want no tags", got, err)
+	}
+}
@@ -0,0 +1,46 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+// defaultMultipartThreshold is the size above which
+// Upload switches to a multipart upload.
+const defaultMultipartThreshold = 100 * 1024 * 1024
+
+// SmartUploadInput is passed to Upload as a parameter.
+type SmartUploadInput struct {
+	UploadInput
+
+	// MultipartThreshold is the largest body uploaded with a single
+	// PUT request, defaults to 100 MB.
+	MultipartThreshold int64
+	// PartSize is the size of the parts of a multipart upload,
+	// see MultipartUploadInput.
+	PartSize int64
+}
+
+// Upload uploads the body with FilePut if it is no larger than
+// u.MultipartThreshold, and with MultipartUpload otherwise, so that
+// callers do not have to pick one depending on the size of the body.
+func (s3 *S3) Upload(u SmartUploadInput) (UploadResponse, error) {
+	if err := u.validate(); err != nil {
+		return UploadResponse{}, err
+	}
+
+	threshold := u.MultipartThreshold
+	if threshold == 0 {
+		threshold = defaultMultipartThreshold
+	}
+
+	fSize, err := detectFileSize(u.Body)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+	if fSize <= threshold {
+		return s3.FilePut(u.UploadInput)
+	}
+	return s3.MultipartUpload(MultipartUploadInput{
+		UploadInput: u.UploadInput,
+		PartSize:    u.PartSize,
+	})
+}
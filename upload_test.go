@@ -0,0 +1,50 @@
+package gos3
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestS3_Upload(t *testing.T) {
+	t.Run("put", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut || r.URL.RawQuery != "" {
+				t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			}
+			ioutil.ReadAll(r.Body)
+		}))
+		defer ts.Close()
+
+		s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+		s3.SetEndpoint(ts.URL)
+
+		if _, err := s3.Upload(SmartUploadInput{
+			UploadInput: UploadInput{Bucket: "bucket", ObjectKey: "key", Body: bytes.NewReader([]byte("hello world"))},
+		}); err != nil {
+			t.Fatalf("S3.Upload() error = %v", err)
+		}
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		m := &multipartServer{t: t, parts: map[string][]byte{}}
+		ts := httptest.NewServer(m)
+		defer ts.Close()
+
+		s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+		s3.SetEndpoint(ts.URL)
+
+		data := bytes.Repeat([]byte("0123456789"), minPartSize/5)
+		if _, err := s3.Upload(SmartUploadInput{
+			UploadInput:        UploadInput{Bucket: "bucket", ObjectKey: "key", Body: bytes.NewReader(data)},
+			MultipartThreshold: minPartSize,
+		}); err != nil {
+			t.Fatalf("S3.Upload() error = %v", err)
+		}
+		if len(m.parts) != 2 || !bytes.Equal(m.complete, data) {
+			t.Errorf("S3.Upload() uploaded %d parts", len(m.parts))
+		}
+	})
+}
@@ -0,0 +1,141 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"sync"
+)
+
+// MultipartUploadState is the state of a multipart upload started by
+// a MultipartUploader. It can be saved, eg. as JSON, to resume the
+// upload with ResumeMultipartUpload once the process was restarted.
+type MultipartUploadState struct {
+	UploadID       string
+	Bucket         string
+	Key            string
+	CompletedParts []CompletedPart
+}
+
+// MultipartUploader uploads a body like MultipartUpload, but keeps
+// track of the uploaded parts. Unlike MultipartUpload, it does not
+// abort the upload when a part fails, so that it can be resumed,
+// possibly by another process from a saved state, or aborted
+// with Abort.
+type MultipartUploader struct {
+	s3 *S3
+	in MultipartUploadInput
+
+	mu    sync.Mutex
+	state MultipartUploadState
+}
+
+// NewMultipartUploader returns a MultipartUploader of u.
+func (s3 *S3) NewMultipartUploader(u MultipartUploadInput) *MultipartUploader {
+	return &MultipartUploader{s3: s3, in: u}
+}
+
+// Upload starts a new multipart upload and uploads the whole body.
+func (m *MultipartUploader) Upload() (UploadResponse, error) {
+	partSize, err := m.in.validatedPartSize()
+	if err != nil {
+		return UploadResponse{}, err
+	}
+	uploadID, err := m.s3.initiateMultipartUpload(m.in.UploadInput)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+
+	m.mu.Lock()
+	m.state = MultipartUploadState{
+		UploadID: uploadID,
+		Bucket:   m.in.Bucket,
+		Key:      m.in.ObjectKey,
+	}
+	m.mu.Unlock()
+	return m.upload(partSize, nil)
+}
+
+// SaveState returns the state of the upload,
+// with the parts uploaded so far in order.
+func (m *MultipartUploader) SaveState() MultipartUploadState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := m.state
+	state.CompletedParts = append([]CompletedPart(nil), m.state.CompletedParts...)
+	sort.Slice(state.CompletedParts, func(i, j int) bool {
+		return state.CompletedParts[i].PartNumber < state.CompletedParts[j].PartNumber
+	})
+	return state
+}
+
+// ResumeMultipartUpload resumes the upload of state, which must have
+// been started with the same body and part size. The parts listed
+// by ListParts are read from the body but not sent again, the
+// others are uploaded before the upload is completed.
+func (m *MultipartUploader) ResumeMultipartUpload(state MultipartUploadState) (UploadResponse, error) {
+	if state.Bucket != m.in.Bucket || state.Key != m.in.ObjectKey {
+		return UploadResponse{}, errors.New("state is of an upload to another object")
+	}
+	partSize, err := m.in.validatedPartSize()
+	if err != nil {
+		return UploadResponse{}, err
+	}
+
+	// S3 knows best which parts were uploaded, those
+	// of the saved state may not all have made it.
+	listed, err := m.s3.ListParts(state.Bucket, state.Key, state.UploadID)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+	uploaded := make(map[int]string, len(listed))
+	state.CompletedParts = make([]CompletedPart, len(listed))
+	for i, p := range listed {
+		uploaded[p.PartNumber] = p.ETag
+		state.CompletedParts[i] = CompletedPart{PartNumber: p.PartNumber, ETag: p.ETag}
+	}
+
+	m.mu.Lock()
+	m.state = state
+	m.mu.Unlock()
+
+	if _, err := m.in.Body.Seek(0, io.SeekStart); err != nil {
+		return UploadResponse{}, err
+	}
+	return m.upload(partSize, uploaded)
+}
+
+// Abort aborts the upload, freeing the parts uploaded so far.
+func (m *MultipartUploader) Abort() error {
+	state := m.SaveState()
+	return m.s3.AbortMultipartUpload(state.Bucket, state.Key, state.UploadID)
+}
+
+// upload uploads the parts of the body which are not
+// in uploaded, then completes the upload.
+func (m *MultipartUploader) upload(partSize int64, uploaded map[int]string) (UploadResponse, error) {
+	uploadID := m.SaveState().UploadID
+
+	parts, err := partUploader{
+		s3:          m.s3,
+		u:           m.in.UploadInput,
+		uploadID:    uploadID,
+		partSize:    partSize,
+		concurrency: m.in.Concurrency,
+		maxRetries:  m.in.MaxPartRetries,
+		uploaded:    uploaded,
+		onPart: func(part CompletedPart) {
+			m.mu.Lock()
+			m.state.CompletedParts = append(m.state.CompletedParts, part)
+			m.mu.Unlock()
+		},
+	}.upload(m.in.Body)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+	return m.s3.completeMultipartUpload(m.in.Bucket, m.in.ObjectKey, uploadID, parts)
+}
@@ -0,0 +1,83 @@
+package gos3
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestMultipartUploader_Resume(t *testing.T) {
+	m := &multipartServer{t: t, parts: map[string][]byte{}}
+	var (
+		mu      sync.Mutex
+		failing = true
+		puts    int
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			mu.Lock()
+			puts++
+			fail := failing && r.URL.Query().Get("partNumber") == "2"
+			mu.Unlock()
+			if fail {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+		}
+		m.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	data := bytes.Repeat([]byte("0123456789"), minPartSize/10+1)
+	input := MultipartUploadInput{
+		UploadInput: UploadInput{
+			Bucket:    "bucket",
+			ObjectKey: "key",
+			Body:      bytes.NewReader(data),
+		},
+	}
+	uploader := s3.NewMultipartUploader(input)
+	if _, err := uploader.Upload(); err == nil {
+		t.Fatalf("MultipartUploader.Upload() expected an error for a failing part")
+	}
+	if m.aborted {
+		t.Errorf("MultipartUploader.Upload() aborted the upload")
+	}
+	state := uploader.SaveState()
+	want := MultipartUploadState{
+		UploadID:       "upload-id",
+		Bucket:         "bucket",
+		Key:            "key",
+		CompletedParts: []CompletedPart{{PartNumber: 1, ETag: `"etag-1"`}},
+	}
+	if !reflect.DeepEqual(state, want) {
+		t.Errorf("MultipartUploader.SaveState() = %+v, want %+v", state, want)
+	}
+
+	// Resume from the saved state, as a restarted process would.
+	mu.Lock()
+	failing, puts = false, 0
+	mu.Unlock()
+	input.Body = bytes.NewReader(data)
+	resp, err := s3.NewMultipartUploader(input).ResumeMultipartUpload(state)
+	if err != nil {
+		t.Fatalf("MultipartUploader.ResumeMultipartUpload() error = %v", err)
+	}
+	if resp.ETag != `"final"` || puts != 1 {
+		t.Errorf("MultipartUploader.ResumeMultipartUpload() = %v after uploading %d parts, want 1", resp, puts)
+	}
+	if !bytes.Equal(m.complete, data) {
+		t.Errorf("MultipartUploader.ResumeMultipartUpload() assembled object does not match the input")
+	}
+
+	state.Key = "other"
+	if _, err := s3.NewMultipartUploader(input).ResumeMultipartUpload(state); err == nil {
+		t.Errorf("MultipartUploader.ResumeMultipartUpload() expected an error for another key")
+	}
+}
@@ -0,0 +1,125 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// URLBuilder builds the URL of an object, or of a bucket if key
+// is empty. Requests are signed for the host of the built URL.
+type URLBuilder interface {
+	Build(region, bucket, key string) string
+}
+
+// PathStyleURLBuilder builds path-style URLs,
+// eg. https://s3.<region>.amazonaws.com/<bucket>/<key>.
+type PathStyleURLBuilder struct {
+	// Endpoint replaces the AWS endpoint of the region,
+	// eg. http://localhost:9000 for a local MinIO.
+	Endpoint string
+}
+
+// Build implements URLBuilder.
+func (b PathStyleURLBuilder) Build(region, bucket, key string) string {
+	uri := b.Endpoint
+	if uri == "" {
+		uri = "https://s3." + region + ".amazonaws.com"
+	}
+	return joinKey(uri+"/"+bucket, key)
+}
+
+// VirtualHostedURLBuilder builds virtual-hosted-style URLs,
+// eg. https://<bucket>.s3.<region>.amazonaws.com/<key>.
+type VirtualHostedURLBuilder struct {
+	// Endpoint replaces the AWS endpoint of the region. The
+	// bucket is prepended to its host, eg. http://minio.local:9000
+	// becomes http://<bucket>.minio.local:9000.
+	Endpoint string
+}
+
+// Build implements URLBuilder.
+func (b VirtualHostedURLBuilder) Build(region, bucket, key string) string {
+	uri := b.Endpoint
+	if uri == "" {
+		uri = "https://s3." + region + ".amazonaws.com"
+	}
+	if bucket != "" {
+		i := strings.Index(uri, "://") + len("://")
+		uri = uri[:i] + bucket + "." + uri[i:]
+	}
+	return joinKey(uri, key)
+}
+
+// legacyURLBuilder builds URLs from a URIFormat, a format
+// string receiving the region and the bucket as arguments.
+type legacyURLBuilder string
+
+// Build implements URLBuilder.
+func (b legacyURLBuilder) Build(region, bucket, key string) string {
+	return joinKey(fmt.Sprintf(string(b), region, bucket), key)
+}
+
+func joinKey(uri, key string) string {
+	if key == "" {
+		return uri
+	}
+	return uri + "/" + key
+}
+
+// SetURLBuilder sets the URLBuilder used for all requests. It takes
+// precedence over SetEndpoint, SetURLStyle, URIFormat and the
+// Use* methods selecting an AWS endpoint.
+func (s3 *S3) SetURLBuilder(b URLBuilder) *S3 {
+	s3.urlBuilder = b
+	return s3
+}
+
+// builder returns the URLBuilder for requests
+// to bucket, as configured on the client.
+func (s3 *S3) builder(bucket string) URLBuilder {
+	switch {
+	case s3.urlBuilder != nil:
+		return s3.urlBuilder
+	case s3.URLStyle == VirtualHostedStyle && len(s3.Endpoint) > 0:
+		return VirtualHostedURLBuilder{Endpoint: s3.Endpoint}
+	case s3.accelerate && bucket != "":
+		return VirtualHostedURLBuilder{Endpoint: "https://" + s3.accelerateHost()}
+	case s3.URLStyle == VirtualHostedStyle:
+		return VirtualHostedURLBuilder{Endpoint: "https://" + fmt.Sprintf(s3.hostFormat(), s3.Region)}
+	case len(s3.Endpoint) > 0:
+		return PathStyleURLBuilder{Endpoint: s3.Endpoint}
+	default:
+		return legacyURLBuilder(s3.URIFormat)
+	}
+}
+
+// resolve returns the URL of the object key in bucket, or of the
+// bucket if key is empty, and the scope its requests are signed for,
+// which is zero for the client's. Unless a URLBuilder is set, buckets
+// given by the ARN of an access point resolve to the access point.
+func (s3 *S3) resolve(bucket, key string) (string, signingScope, error) {
+	if s3.urlBuilder == nil && isARN(bucket) {
+		return s3.accessPointURL(bucket, key)
+	}
+	return s3.builder(bucket).Build(s3.Region, bucket, key), signingScope{}, nil
+}
+
+// newRequest returns a request for the object key in bucket, or for
+// the bucket if key is empty, with query appended to the URL. It
+// carries the scope for which signRequest signs it in its context.
+func (s3 *S3) newRequest(ctx context.Context, method, bucket, key, query string, body io.Reader) (*http.Request, error) {
+	uri, scope, err := s3.resolve(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	if scope != (signingScope{}) {
+		ctx = context.WithValue(ctx, signingScopeKey{}, scope)
+	}
+	return http.NewRequestWithContext(ctx, method, uri+query, body)
+}
@@ -0,0 +1,37 @@
+package gos3
+
+import "testing"
+
+func TestURLBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder URLBuilder
+		bucket  string
+		key     string
+		want    string
+	}{
+		{"path style", PathStyleURLBuilder{}, "bucket", "xyz/image.png", "https://s3.us-east-1.amazonaws.com/bucket/xyz/image.png"},
+		{"path style bucket", PathStyleURLBuilder{}, "bucket", "", "https://s3.us-east-1.amazonaws.com/bucket"},
+		{"path style endpoint", PathStyleURLBuilder{Endpoint: "http://localhost:9000"}, "bucket", "test.txt", "http://localhost:9000/bucket/test.txt"},
+		{"virtual hosted", VirtualHostedURLBuilder{}, "bucket", "xyz/image.png", "https://bucket.s3.us-east-1.amazonaws.com/xyz/image.png"},
+		{"virtual hosted no bucket", VirtualHostedURLBuilder{}, "", "", "https://s3.us-east-1.amazonaws.com"},
+		{"virtual hosted endpoint", VirtualHostedURLBuilder{Endpoint: "http://minio.local:9000"}, "bucket", "test.txt", "http://bucket.minio.local:9000/test.txt"},
+		{"legacy", legacyURLBuilder("http://%s.example.com/%s"), "bucket", "test.txt", "http://us-east-1.example.com/bucket/test.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.builder.Build("us-east-1", tt.bucket, tt.key); got != tt.want {
+				t.Errorf("URLBuilder.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestS3_SetURLBuilder(t *testing.T) {
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint("http://localhost:9000")
+	s3.SetURLBuilder(VirtualHostedURLBuilder{Endpoint: "http://minio.local:9000"})
+	if got := s3.getURL("bucket", "xyz/image.png"); got != "http://bucket.minio.local:9000/xyz/image.png" {
+		t.Errorf("S3.getURL() = %v", got)
+	}
+}
@@ -0,0 +1,142 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Versioning statuses returned by GetVersioningStatus.
+// Buckets on which versioning was never enabled have
+// an empty status.
+const (
+	VersioningEnabled   = "Enabled"
+	VersioningSuspended = "Suspended"
+)
+
+// versioningConfiguration is the XML representation
+// of the versioning state of a bucket.
+type versioningConfiguration struct {
+	XMLName xml.Name `xml:"VersioningConfiguration"`
+	Status  string   `xml:"Status,omitempty"`
+}
+
+// ObjectVersion describes a version of an object
+// returned by ListObjectVersions.
+type ObjectVersion struct {
+	Key          string    `xml:"Key"`
+	VersionID    string    `xml:"VersionId"`
+	IsLatest     bool      `xml:"IsLatest"`
+	LastModified time.Time `xml:"LastModified"`
+	ETag         string    `xml:"ETag"`
+	Size         int64     `xml:"Size"`
+}
+
+// listVersionsResult receives the ListVersionsResult XML.
+type listVersionsResult struct {
+	Versions            []ObjectVersion `xml:"Version"`
+	IsTruncated         bool            `xml:"IsTruncated"`
+	NextKeyMarker       string          `xml:"NextKeyMarker"`
+	NextVersionIDMarker string          `xml:"NextVersionIdMarker"`
+}
+
+// versionQuery returns the query string selecting versionID,
+// or an empty string for the latest version.
+func versionQuery(versionID string) string {
+	if versionID == "" {
+		return ""
+	}
+	return "?versionId=" + url.QueryEscape(versionID)
+}
+
+// ListObjectVersions makes GET calls to list every version of
+// the objects whose key starts with prefix. Delete markers
+// are not included.
+func (s3 *S3) ListObjectVersions(bucket, prefix string) ([]ObjectVersion, error) {
+	var versions []ObjectVersion
+	query := url.Values{}
+	query.Set("versions", "")
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	for {
+		result, err := s3.listObjectVersions(bucket, query)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, result.Versions...)
+
+		if !result.IsTruncated || result.NextKeyMarker == "" {
+			return versions, nil
+		}
+		query.Set("key-marker", result.NextKeyMarker)
+		query.Set("version-id-marker", result.NextVersionIDMarker)
+	}
+}
+
+// listObjectVersions fetches a single page of ListObjectVersions.
+func (s3 *S3) listObjectVersions(bucket string, query url.Values) (listVersionsResult, error) {
+	req, err := s3.newRequest(
+		context.Background(), http.MethodGet, bucket, "", "?"+query.Encode(), nil,
+	)
+	if err != nil {
+		return listVersionsResult{}, err
+	}
+
+	if err := s3.signRequest(req); err != nil {
+		return listVersionsResult{}, err
+	}
+
+	res, err := s3.do(req)
+	if err != nil {
+		return listVersionsResult{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return listVersionsResult{}, newResponseError(res)
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return listVersionsResult{}, err
+	}
+
+	var result listVersionsResult
+	if err := xml.Unmarshal(data, &result); err != nil {
+		return listVersionsResult{}, err
+	}
+	return result, nil
+}
+
+// EnableVersioning makes a PUT call to enable versioning on a bucket.
+func (s3 *S3) EnableVersioning(bucket string) error {
+	return s3.putVersioning(bucket, VersioningEnabled)
+}
+
+// SuspendVersioning makes a PUT call to stop creating new versions
+// in a bucket. Existing versions are kept.
+func (s3 *S3) SuspendVersioning(bucket string) error {
+	return s3.putVersioning(bucket, VersioningSuspended)
+}
+
+func (s3 *S3) putVersioning(bucket, status string) error {
+	return s3.doXML(http.MethodPut, bucket, "", "?versioning", nil, versioningConfiguration{Status: status}, nil)
+}
+
+// GetVersioningStatus makes a GET call and returns VersioningEnabled,
+// VersioningSuspended, or an empty string if versioning was never
+// enabled on the bucket.
+func (s3 *S3) GetVersioningStatus(bucket string) (string, error) {
+	var config versioningConfiguration
+	if err := s3.doXML(http.MethodGet, bucket, "", "?versioning", nil, nil, &config); err != nil {
+		return "", err
+	}
+	return config.Status, nil
+}
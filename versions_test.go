@@ -0,0 +1,114 @@
+package gos3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestS3_ListObjectVersions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if _, ok := q["versions"]; !ok || q.Get("prefix") != "logs/" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if q.Get("key-marker") == "" {
+			w.Write([]byte(`<ListVersionsResult>
+<IsTruncated>true</IsTruncated>
+<NextKeyMarker>logs/a.txt</NextKeyMarker>
+<NextVersionIdMarker>v1</NextVersionIdMarker>
+<Version><Key>logs/a.txt</Key><VersionId>v2</VersionId><IsLatest>true</IsLatest><Size>3</Size></Version>
+<DeleteMarker><Key>logs/b.txt</Key><VersionId>v9</VersionId></DeleteMarker>
+</ListVersionsResult>`))
+			return
+		}
+		if q.Get("version-id-marker") != "v1" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`<ListVersionsResult>
+<IsTruncated>false</IsTruncated>
+<Version><Key>logs/a.txt</Key><VersionId>v1</VersionId><IsLatest>false</IsLatest><Size>2</Size></Version>
+</ListVersionsResult>`))
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	versions, err := s3.ListObjectVersions("bucket", "logs/")
+	if err != nil {
+		t.Fatalf("S3.ListObjectVersions() error = %v", err)
+	}
+	if len(versions) != 2 || versions[0].VersionID != "v2" || !versions[0].IsLatest || versions[1].Size != 2 {
+		t.Errorf("S3.ListObjectVersions() = %+v", versions)
+	}
+}
+
+func TestS3_VersionID(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("versionId"); got != "v1" {
+			t.Errorf("%s versionId = %q, want v1", r.Method, got)
+		}
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte("old"))
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	body, err := s3.FileDownload(DownloadInput{Bucket: "bucket", ObjectKey: "a.txt", VersionID: "v1"})
+	if err != nil {
+		t.Fatalf("S3.FileDownload() error = %v", err)
+	}
+	got, _ := ioutil.ReadAll(body)
+	body.Close()
+	if string(got) != "old" {
+		t.Errorf("S3.FileDownload() = %s", got)
+	}
+
+	if err := s3.FileDelete(DeleteInput{Bucket: "bucket", ObjectKey: "a.txt", VersionID: "v1"}); err != nil {
+		t.Errorf("S3.FileDelete() error = %v", err)
+	}
+}
+
+func TestS3_Versioning(t *testing.T) {
+	stored := []byte(`<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"/>`)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["versioning"]; !ok || r.URL.Path != "/bucket" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			stored, _ = ioutil.ReadAll(r.Body)
+		case http.MethodGet:
+			w.Write(stored)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	if status, err := s3.GetVersioningStatus("bucket"); status != "" || err != nil {
+		t.Errorf("S3.GetVersioningStatus() = %q, %v, want empty", status, err)
+	}
+
+	if err := s3.EnableVersioning("bucket"); err != nil {
+		t.Fatalf("S3.EnableVersioning() error = %v", err)
+	}
+	if status, err := s3.GetVersioningStatus("bucket"); status != VersioningEnabled || err != nil {
+		t.Errorf("S3.GetVersioningStatus() = %q, %v, want %q", status, err, VersioningEnabled)
+	}
+
+	if err := s3.SuspendVersioning("bucket"); err != nil {
+		t.Fatalf("S3.SuspendVersioning() error = %v", err)
+	}
+	if status, err := s3.GetVersioningStatus("bucket"); status != VersioningSuspended || err != nil {
+		t.Errorf("S3.GetVersioningStatus() = %q, %v, want %q", status, err, VersioningSuspended)
+	}
+}
@@ -0,0 +1,158 @@
+// LICENSE MIT
+// Copyright (c) 2018, Rohan Verma <hello@rohanverma.net>
+
+package gos3
+
+import (
+	"encoding/xml"
+	"net/http"
+)
+
+// WebsiteConfig configures a bucket to be served as a static website.
+type WebsiteConfig struct {
+	// IndexDocument is served for requests to a directory,
+	// eg. index.html.
+	IndexDocument string
+	// ErrorDocument is the key of the object
+	// served when an error occurs.
+	ErrorDocument string
+	RoutingRules  []RoutingRule
+}
+
+// RoutingRule redirects the requests matching its condition,
+// or all the requests if the condition is empty.
+type RoutingRule struct {
+	// Condition
+	KeyPrefixEquals             string
+	HTTPErrorCodeReturnedEquals string
+
+	// Redirect, only one of ReplaceKeyPrefixWith
+	// and ReplaceKeyWith may be set.
+	HostName             string
+	HTTPRedirectCode     string
+	Protocol             string
+	ReplaceKeyPrefixWith string
+	ReplaceKeyWith       string
+}
+
+// websiteConfiguration is the XML representation of a WebsiteConfig.
+// Optional elements are pointers, so that they are omitted when empty.
+type websiteConfiguration struct {
+	XMLName       xml.Name       `xml:"WebsiteConfiguration"`
+	IndexDocument *indexDocument `xml:"IndexDocument"`
+	ErrorDocument *errorDocument `xml:"ErrorDocument"`
+	RoutingRules  *routingRules  `xml:"RoutingRules"`
+}
+
+type indexDocument struct {
+	Suffix string `xml:"Suffix"`
+}
+
+type errorDocument struct {
+	Key string `xml:"Key"`
+}
+
+type routingRules struct {
+	Rules []routingRule `xml:"RoutingRule"`
+}
+
+// routingRule is the XML representation of a RoutingRule.
+type routingRule struct {
+	Condition *routingRuleCondition `xml:"Condition"`
+	Redirect  routingRuleRedirect   `xml:"Redirect"`
+}
+
+type routingRuleCondition struct {
+	KeyPrefixEquals             string `xml:"KeyPrefixEquals,omitempty"`
+	HTTPErrorCodeReturnedEquals string `xml:"HttpErrorCodeReturnedEquals,omitempty"`
+}
+
+type routingRuleRedirect struct {
+	HostName             string `xml:"HostName,omitempty"`
+	HTTPRedirectCode     string `xml:"HttpRedirectCode,omitempty"`
+	Protocol             string `xml:"Protocol,omitempty"`
+	ReplaceKeyPrefixWith string `xml:"ReplaceKeyPrefixWith,omitempty"`
+	ReplaceKeyWith       string `xml:"ReplaceKeyWith,omitempty"`
+}
+
+// newWebsiteConfiguration returns the XML representation of cfg.
+func newWebsiteConfiguration(cfg WebsiteConfig) websiteConfiguration {
+	var c websiteConfiguration
+	if cfg.IndexDocument != "" {
+		c.IndexDocument = &indexDocument{Suffix: cfg.IndexDocument}
+	}
+	if cfg.ErrorDocument != "" {
+		c.ErrorDocument = &errorDocument{Key: cfg.ErrorDocument}
+	}
+	if len(cfg.RoutingRules) > 0 {
+		c.RoutingRules = &routingRules{}
+		for _, r := range cfg.RoutingRules {
+			rule := routingRule{Redirect: routingRuleRedirect{
+				HostName:             r.HostName,
+				HTTPRedirectCode:     r.HTTPRedirectCode,
+				Protocol:             r.Protocol,
+				ReplaceKeyPrefixWith: r.ReplaceKeyPrefixWith,
+				ReplaceKeyWith:       r.ReplaceKeyWith,
+			}}
+			if r.KeyPrefixEquals != "" || r.HTTPErrorCodeReturnedEquals != "" {
+				rule.Condition = &routingRuleCondition{
+					KeyPrefixEquals:             r.KeyPrefixEquals,
+					HTTPErrorCodeReturnedEquals: r.HTTPErrorCodeReturnedEquals,
+				}
+			}
+			c.RoutingRules.Rules = append(c.RoutingRules.Rules, rule)
+		}
+	}
+	return c
+}
+
+// config returns the WebsiteConfig represented by c.
+func (c websiteConfiguration) config() WebsiteConfig {
+	var cfg WebsiteConfig
+	if c.IndexDocument != nil {
+		cfg.IndexDocument = c.IndexDocument.Suffix
+	}
+	if c.ErrorDocument != nil {
+		cfg.ErrorDocument = c.ErrorDocument.Key
+	}
+	if c.RoutingRules != nil {
+		for _, r := range c.RoutingRules.Rules {
+			rule := RoutingRule{
+				HostName:             r.Redirect.HostName,
+				HTTPRedirectCode:     r.Redirect.HTTPRedirectCode,
+				Protocol:             r.Redirect.Protocol,
+				ReplaceKeyPrefixWith: r.Redirect.ReplaceKeyPrefixWith,
+				ReplaceKeyWith:       r.Redirect.ReplaceKeyWith,
+			}
+			if r.Condition != nil {
+				rule.KeyPrefixEquals = r.Condition.KeyPrefixEquals
+				rule.HTTPErrorCodeReturnedEquals = r.Condition.HTTPErrorCodeReturnedEquals
+			}
+			cfg.RoutingRules = append(cfg.RoutingRules, rule)
+		}
+	}
+	return cfg
+}
+
+// PutBucketWebsite makes a PUT call to replace
+// the website configuration of a bucket with cfg.
+func (s3 *S3) PutBucketWebsite(bucket string, cfg WebsiteConfig) error {
+	return s3.doXML(http.MethodPut, bucket, "", "?website", nil, newWebsiteConfiguration(cfg), nil)
+}
+
+// GetBucketWebsite makes a GET call and returns the website
+// configuration of a bucket. If the bucket is not configured
+// as a website, the returned error matches ErrNotFound.
+func (s3 *S3) GetBucketWebsite(bucket string) (WebsiteConfig, error) {
+	var config websiteConfiguration
+	if err := s3.doXML(http.MethodGet, bucket, "", "?website", nil, nil, &config); err != nil {
+		return WebsiteConfig{}, err
+	}
+	return config.config(), nil
+}
+
+// DeleteBucketWebsite makes a DELETE call to remove the website
+// configuration of a bucket, which stops it being served as a website.
+func (s3 *S3) DeleteBucketWebsite(bucket string) error {
+	return s3.doXML(http.MethodDelete, bucket, "", "?website", nil, nil, nil)
+}
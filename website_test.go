@@ -0,0 +1,92 @@
+package gos3
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestS3_BucketWebsite(t *testing.T) {
+	var stored []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["website"]; !ok || r.URL.Path != "/bucket" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			stored, _ = ioutil.ReadAll(r.Body)
+		case http.MethodGet:
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte("<Error><Code>NoSuchWebsiteConfiguration</Code></Error>"))
+				return
+			}
+			w.Write(stored)
+		case http.MethodDelete:
+			stored = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	s3 := New("us-east-1", "AccessKey", "SuperSecretKey")
+	s3.SetEndpoint(ts.URL)
+
+	cfg := WebsiteConfig{
+		IndexDocument: "index.html",
+		ErrorDocument: "error.html",
+		RoutingRules: []RoutingRule{{
+			KeyPrefixEquals:      "docs/",
+			ReplaceKeyPrefixWith: "documents/",
+		}},
+	}
+	if err := s3.PutBucketWebsite("bucket", cfg); err != nil {
+		t.Fatalf("S3.PutBucketWebsite() error = %v", err)
+	}
+	want := `<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><ErrorDocument><Key>error.html</Key></ErrorDocument>` +
+		`<RoutingRules><RoutingRule><Condition><KeyPrefixEquals>docs/</KeyPrefixEquals></Condition>` +
+		`<Redirect><ReplaceKeyPrefixWith>documents/</ReplaceKeyPrefixWith></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`
+	if string(stored) != want {
+		t.Errorf("S3.PutBucketWebsite() sent = %s, want %s", stored, want)
+	}
+
+	got, err := s3.GetBucketWebsite("bucket")
+	if err != nil {
+		t.Fatalf("S3.GetBucketWebsite() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("S3.GetBucketWebsite() = %+v, want %+v", got, cfg)
+	}
+
+	// Empty elements are rejected by S3.
+	cfg = WebsiteConfig{
+		IndexDocument: "index.html",
+		RoutingRules:  []RoutingRule{{HostName: "example.com"}},
+	}
+	if err := s3.PutBucketWebsite("bucket", cfg); err != nil {
+		t.Fatalf("S3.PutBucketWebsite() error = %v", err)
+	}
+	want = `<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument>` +
+		`<RoutingRules><RoutingRule><Redirect><HostName>example.com</HostName></Redirect></RoutingRule></RoutingRules></WebsiteConfiguration>`
+	if string(stored) != want {
+		t.Errorf("S3.PutBucketWebsite() sent = %s, want %s", stored, want)
+	}
+
+	got, err = s3.GetBucketWebsite("bucket")
+	if err != nil {
+		t.Fatalf("S3.GetBucketWebsite() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("S3.GetBucketWebsite() = %+v, want %+v", got, cfg)
+	}
+
+	if err := s3.DeleteBucketWebsite("bucket"); err != nil {
+		t.Fatalf("S3.DeleteBucketWebsite() error = %v", err)
+	}
+	if _, err := s3.GetBucketWebsite("bucket"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("S3.GetBucketWebsite() error = %v, want ErrNotFound", err)
+	}
+}